@@ -1,11 +1,36 @@
 package cmd
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/giocirque/gocovrpt/formats"
+	"github.com/giocirque/gocovrpt/lib"
+)
 
 const (
-	FormatHtml  = "html"
-	FormatBadge = "badge"
-	FormatValue = "value"
+	FormatHtml       = "html"
+	FormatBadge      = "badge"
+	FormatValue      = "value"
+	FormatCobertura  = "cobertura"
+	FormatLcov       = "lcov"
+	FormatJson       = "json"
+	FormatYaml       = "yaml"
+	FormatXml        = "xml"
+	FormatClover     = "clover"
+	FormatJacoco     = "jacoco"
+	FormatSonar      = "sonar"
+	FormatMarkdown   = "markdown"
+	FormatText       = "text"
+	FormatFunc       = "func"
+	FormatCsv        = "csv"
+	FormatGithub     = "github"
+	FormatSarif      = "sarif"
+	FormatPrometheus = "prometheus"
+	FormatShields    = "shields"
+	FormatTeamcity   = "teamcity"
+	FormatCodecov    = "codecov"
+	FormatCoveralls  = "coveralls"
+	FormatSource     = "source"
 )
 
 const (
@@ -13,7 +38,27 @@ const (
 	LevelSummary = "summary"
 )
 
-var allFormats = []string{FormatHtml, FormatBadge, FormatValue}
+var allFormats = []string{FormatHtml, FormatBadge, FormatValue, FormatCobertura, FormatLcov, FormatJson, FormatYaml, FormatXml, FormatClover, FormatJacoco, FormatSonar, FormatMarkdown, FormatText, FormatFunc, FormatCsv, FormatGithub, FormatSarif, FormatPrometheus, FormatShields, FormatTeamcity, FormatCodecov, FormatCoveralls, FormatSource}
+
+// The file extensions appended to the default output path for single file formats.
+var defaultExts = map[string]string{
+	FormatBadge:      ".svg",
+	FormatCobertura:  ".xml",
+	FormatLcov:       ".info",
+	FormatJson:       ".json",
+	FormatYaml:       ".yaml",
+	FormatXml:        ".xml",
+	FormatClover:     ".xml",
+	FormatJacoco:     ".xml",
+	FormatSonar:      ".xml",
+	FormatMarkdown:   ".md",
+	FormatCsv:        ".csv",
+	FormatSarif:      ".sarif",
+	FormatPrometheus: ".prom",
+	FormatShields:    ".json",
+	FormatCodecov:    ".json",
+	FormatCoveralls:  ".json",
+}
 
 func AllFormats() []string {
 	return allFormats
@@ -23,6 +68,25 @@ func AllFormatsString() string {
 	return strings.Join(allFormats, ", ")
 }
 
+// The formats written to stdout unless an output is explicitly set.
+var stdoutFormats = []string{FormatText, FormatFunc, FormatGithub, FormatTeamcity, FormatSource}
+
+// IsStdoutFormat returns true if the format writes to stdout by default.
+func IsStdoutFormat(format string) bool {
+	for _, f := range stdoutFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DefaultOutputExt returns the file extension for the default output path of the given format, if any.
+func DefaultOutputExt(format string) string {
+	return defaultExts[format]
+}
+
 func IsValidFormat(value string) bool {
 	for _, f := range allFormats {
 		if f == value {
@@ -52,3 +116,47 @@ func IsValidLevel(value string) bool {
 
 	return false
 }
+
+var allCsvKinds = []string{formats.CsvKindAll, formats.CsvKindFile, formats.CsvKindFolder}
+
+func AllCsvKinds() []string {
+	return allCsvKinds
+}
+
+func AllCsvKindsString() string {
+	return strings.Join(allCsvKinds, ", ")
+}
+
+func IsValidCsvKind(value string) bool {
+	for _, k := range allCsvKinds {
+		if k == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+var allCoerceModes = []string{lib.ModeSet}
+
+func AllCoerceModes() []string {
+	return allCoerceModes
+}
+
+func AllCoerceModesString() string {
+	return strings.Join(allCoerceModes, ", ")
+}
+
+func IsValidCoerceMode(value string) bool {
+	if value == "" {
+		// Not coercing is always valid.
+		return true
+	}
+	for _, m := range allCoerceModes {
+		if m == value {
+			return true
+		}
+	}
+
+	return false
+}
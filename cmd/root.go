@@ -13,7 +13,6 @@ import (
 	"github.com/giocirque/gocovrpt/formats"
 	"github.com/giocirque/gocovrpt/lib"
 	"github.com/spf13/cobra"
-	"golang.org/x/tools/cover"
 )
 
 var rootCmd = &cobra.Command{
@@ -28,7 +27,17 @@ The input file MUST always be the last argument, and can support multiples separ
 `,
 	Example: `  $ gocovrpt -f html -l [full|summary] -o ./coverage -i ./.build/coverage.raw
   $ gocovrpt -f badge -o ./coverage.svg -i ./.build/coverage.raw
-  $ gocovrpt -f value -o ./covered -i ./.build/coverage.raw`,
+  $ gocovrpt -f value -o ./covered -i ./.build/coverage.raw
+  $ gocovrpt -f shields -o ./coverage.json -i ./.build/coverage.raw
+  $ gocovrpt -f cobertura -o ./coverage.xml -i ./.build/coverage.raw
+  $ gocovrpt -f lcov -o ./lcov.info -i ./.build/coverage.raw
+  $ gocovrpt -f json -o ./coverage.json -i ./.build/coverage.raw
+  $ gocovrpt -f markdown --least-covered 5 -o ./coverage.md -i ./.build/coverage.raw
+  $ gocovrpt -f text --depth 2 -i ./.build/coverage.raw
+  $ gocovrpt -f source --context 3 -i ./.build/coverage.raw
+  $ gunzip -c ./coverage.raw.gz | gocovrpt -f text -i -
+  $ gocovrpt -f html -o ./coverage -i ./unit.raw.gz -i ./integration.tar.gz
+  $ gocovrpt -f html -o ./coverage -i './shards/**/coverage*.out' --input-exclude '**/flaky/**'`,
 	Run: runRootCommand,
 }
 
@@ -38,12 +47,22 @@ func init() {
 		sourceDir = "."
 	}
 
-	rootCmd.Flags().StringArrayP("input", "i", []string{"./.build/coverage.raw"}, "One or more coverage.raw files to read from.")
+	rootCmd.Flags().StringArrayP("input", "i", []string{"./.build/coverage.raw"}, "One or more coverage profiles, LCOV or Cobertura files, GOCOVERDIR directories, or gzip, zstd, tar, or zip files of them to read from, or - for stdin. Glob patterns, including **, and directories are searched for inputs.")
+	rootCmd.Flags().StringArray("input-exclude", []string{}, "Glob patterns of inputs to skip when searching patterns and directories, patterns without a / match file names at any depth.")
 	rootCmd.Flags().StringP("format", "f", "html", fmt.Sprintf("Report format. Available formats: %s", AllFormatsString()))
 	rootCmd.Flags().StringP("level", "l", "full", fmt.Sprintf("Report level. Available levels: %s", AllLevelsString()))
-	rootCmd.Flags().StringP("output", "o", "./.build/coverage", "Output file or directory. For badges, the default is ./.build/coverage.svg.")
+	rootCmd.Flags().StringP("output", "o", "./.build/coverage", "Output file or directory. Single file formats get a matching extension by default, e.g. ./.build/coverage.svg for badges, and text formats go to stdout.")
 	rootCmd.Flags().StringP("source", "s", sourceDir, "The directory containing the covered source files.")
 	rootCmd.Flags().StringP("project", "p", "", "The name of the project.")
+	rootCmd.Flags().Int("least-covered", 0, "For markdown, the number of least covered files to list in a collapsible section.")
+	rootCmd.Flags().Int("depth", 0, "For text, the maximum folder depth to show. Zero shows all folders.")
+	rootCmd.Flags().Int("context", -1, "For source, the lines of context to show around uncovered lines. Negative shows whole files.")
+	rootCmd.Flags().Int("max-annotations", 50, "For github, the maximum number of annotations to write. Zero writes all of them.")
+	rootCmd.Flags().Float64("threshold", 0, "For sarif, the coverage percentage below which partly covered functions are reported.")
+	rootCmd.Flags().String("service-name", "", "For coveralls, the CI service name. Defaults to $COVERALLS_SERVICE_NAME.")
+	rootCmd.Flags().String("service-job-id", "", "For coveralls, the CI service job id. Defaults to $COVERALLS_SERVICE_JOB_ID.")
+	rootCmd.Flags().String("mode-coerce", "", fmt.Sprintf("The coverage mode to report mixed set and count inputs as, instead of failing. Available modes: %s", AllCoerceModesString()))
+	rootCmd.Flags().String("csv-kind", formats.CsvKindAll, fmt.Sprintf("For csv, the kind of rows to write. Available kinds: %s", AllCsvKindsString()))
 }
 
 func Execute() {
@@ -64,21 +83,42 @@ func runRootCommand(cmd *cobra.Command, args []string) {
 		lib.HandleStopError(lib.UnresolvablePathError(path.Dir(config.SourceDir)))
 	}
 
+	inputs, err := lib.FindInputs(config.Input, config.InputExclude, config.Output, os.Stderr)
+	if err != nil {
+		lib.HandleStopError(err)
+	} else if len(inputs) == 0 {
+		lib.HandleStopError(lib.NoInputsError(config.Input))
+	}
+
+	inputProfiles := make([]lib.InputProfiles, 0, len(inputs))
+	for _, input := range inputs {
+		profiles, err := lib.ReadProfiles(input.Path)
+		if err != nil && input.IsScanned {
+			// A file found in a directory only looked like coverage, so it doesn't stop the report.
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", input.Path, err)
+		} else if err != nil {
+			lib.HandleStopError(fmt.Errorf("reading %s: %w", input.Path, err))
+		} else {
+			inputProfiles = append(inputProfiles, lib.NewInputProfiles(input.Path, profiles))
+		}
+	}
+	mode, err := lib.ResolveMode(inputProfiles, config.ModeCoerce)
+	if err != nil {
+		lib.HandleStopError(err)
+	}
+
 	sharedMeta := lib.ReportMeta{
 		ProjectName: config.ProjectName,
 		CommonRoot:  absSourceDir,
 		ParentRoot:  absParentRoot,
+		Mode:        mode,
 	}
 
 	context := lib.NewReportContext(config, sharedMeta, config.Level == LevelFull)
-	for _, input := range config.Input {
-		profiles, err := cover.ParseProfiles(input)
-		if err != nil {
-			lib.HandleStopError(err)
-		} else {
-			for _, profile := range profiles {
-				context.AddProfile(profile)
-			}
+	for _, input := range inputProfiles {
+		lib.CoerceProfiles(input.Profiles, mode)
+		for _, profile := range input.Profiles {
+			context.AddProfile(profile)
 		}
 	}
 	context.UpdateCoverage()
@@ -90,6 +130,46 @@ func runRootCommand(cmd *cobra.Command, args []string) {
 		err = formats.FormatValue(&context)
 	case FormatBadge:
 		err = formats.FormatBadge(&context)
+	case FormatCobertura:
+		err = formats.FormatCobertura(&context)
+	case FormatLcov:
+		err = formats.FormatLcov(&context)
+	case FormatJson:
+		err = formats.FormatJson(&context)
+	case FormatYaml:
+		err = formats.FormatYaml(&context)
+	case FormatXml:
+		err = formats.FormatXml(&context)
+	case FormatClover:
+		err = formats.FormatClover(&context)
+	case FormatJacoco:
+		err = formats.FormatJacoco(&context)
+	case FormatSonar:
+		err = formats.FormatSonar(&context)
+	case FormatMarkdown:
+		err = formats.FormatMarkdown(&context)
+	case FormatText:
+		err = formats.FormatText(&context)
+	case FormatFunc:
+		err = formats.FormatFunc(&context)
+	case FormatCsv:
+		err = formats.FormatCsv(&context)
+	case FormatGithub:
+		err = formats.FormatGithub(&context)
+	case FormatSarif:
+		err = formats.FormatSarif(&context)
+	case FormatPrometheus:
+		err = formats.FormatPrometheus(&context)
+	case FormatShields:
+		err = formats.FormatShields(&context)
+	case FormatTeamcity:
+		err = formats.FormatTeamcity(&context)
+	case FormatCodecov:
+		err = formats.FormatCodecov(&context)
+	case FormatCoveralls:
+		err = formats.FormatCoveralls(&context)
+	case FormatSource:
+		err = formats.FormatSource(&context)
 	}
 
 	lib.HandleStopError(err)
@@ -115,9 +195,14 @@ func validateArgs(cmd *cobra.Command, args []string) (lib.AppConfig, error) {
 	output, err := cmd.LocalFlags().GetString("output")
 	if err != nil {
 		return lib.AppConfig{}, err
-	} else if format == FormatBadge && !cmd.LocalFlags().Changed("output") {
-		// Badge output wasn't explicitly set, so make it an SVG path.
-		output += ".svg"
+	} else if IsStdoutFormat(format) && !cmd.LocalFlags().Changed("output") {
+		// Output wasn't explicitly set for a text format, so write to stdout.
+		output = lib.StdPath
+	} else if !cmd.LocalFlags().Changed("output") {
+		// Output wasn't explicitly set, so give single file formats a matching extension.
+		output += DefaultOutputExt(format)
+	} else if output == lib.StdPath && format == FormatHtml {
+		return lib.AppConfig{}, lib.StdoutOutputError(format)
 	}
 
 	input, err := cmd.LocalFlags().GetStringArray("input")
@@ -125,6 +210,19 @@ func validateArgs(cmd *cobra.Command, args []string) (lib.AppConfig, error) {
 		return lib.AppConfig{}, err
 	}
 
+	inputExclude, err := cmd.LocalFlags().GetStringArray("input-exclude")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	modeCoerce, err := cmd.LocalFlags().GetString("mode-coerce")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+	if !IsValidCoerceMode(modeCoerce) {
+		return lib.AppConfig{}, lib.InvalidArgError("mode-coerce", modeCoerce, AllCoerceModes(), lib.InvalidModeCoerceCode)
+	}
+
 	sourceDir, err := cmd.LocalFlags().GetString("source")
 	if err != nil {
 		return lib.AppConfig{}, err
@@ -139,12 +237,65 @@ func validateArgs(cmd *cobra.Command, args []string) (lib.AppConfig, error) {
 		project = path.Base(path.Dir(fullSourcePath))
 	}
 
+	leastCovered, err := cmd.LocalFlags().GetInt("least-covered")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	depth, err := cmd.LocalFlags().GetInt("depth")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	csvKind, err := cmd.LocalFlags().GetString("csv-kind")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+	if !IsValidCsvKind(csvKind) {
+		return lib.AppConfig{}, lib.InvalidArgError("csv-kind", csvKind, AllCsvKinds(), lib.InvalidCsvKindCode)
+	}
+
+	sourceContext, err := cmd.LocalFlags().GetInt("context")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	maxAnnotations, err := cmd.LocalFlags().GetInt("max-annotations")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	threshold, err := cmd.LocalFlags().GetFloat64("threshold")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	serviceName, err := cmd.LocalFlags().GetString("service-name")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
+	serviceJobId, err := cmd.LocalFlags().GetString("service-job-id")
+	if err != nil {
+		return lib.AppConfig{}, err
+	}
+
 	return lib.AppConfig{
-		Format:      format,
-		Level:       level,
-		Output:      output,
-		Input:       input,
-		SourceDir:   sourceDir,
-		ProjectName: project,
+		Format:         format,
+		Level:          level,
+		Output:         output,
+		Input:          input,
+		InputExclude:   inputExclude,
+		ModeCoerce:     modeCoerce,
+		SourceDir:      sourceDir,
+		ProjectName:    project,
+		LeastCovered:   leastCovered,
+		Depth:          depth,
+		CsvKind:        csvKind,
+		MaxAnnotations: maxAnnotations,
+		Threshold:      threshold,
+		ServiceName:    serviceName,
+		ServiceJobId:   serviceJobId,
+		Context:        sourceContext,
 	}, nil
 }
@@ -29,7 +29,7 @@ func FormatBadge(context *lib.ReportContext) error {
 	model := BadgeModel{
 		ProjectName: context.Config.ProjectName,
 		Percent:     value,
-		Color:       getCoverageColor(value * 3.57),
+		Color:       getCoverageColor(value),
 	}
 	err = templ.ExecuteTemplate(file, "badge.gosvg", model)
 	if err != nil {
@@ -41,18 +41,18 @@ func FormatBadge(context *lib.ReportContext) error {
 
 func getCoverageColor(percent float64) string {
 	color := "#9f9f9f" // light grey
-	if percent >= 5 {
-		color = "#E05D44" // red
-	} else if percent >= 20 {
-		color = "#FE7D37" // orange
-	} else if percent >= 40 {
-		color = "#DFB317" // yellow
-	} else if percent >= 60 {
-		color = "#A4A61D" // yellow-green
+	if percent >= 90 {
+		color = "#4c1" // bright green
 	} else if percent >= 80 {
 		color = "#97CA00" // green
-	} else if percent >= 90 {
-		color = "#4c1" // bright green
+	} else if percent >= 60 {
+		color = "#A4A61D" // yellow-green
+	} else if percent >= 40 {
+		color = "#DFB317" // yellow
+	} else if percent >= 20 {
+		color = "#FE7D37" // orange
+	} else if percent >= 5 {
+		color = "#E05D44" // red
 	}
 	return color
 }
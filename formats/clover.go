@@ -0,0 +1,187 @@
+package formats
+
+import (
+	"encoding/xml"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+type CloverCoverage struct {
+	XMLName   xml.Name      `xml:"coverage"`
+	Generated int64         `xml:"generated,attr"`
+	Clover    string        `xml:"clover,attr"`
+	Project   CloverProject `xml:"project"`
+}
+
+type CloverProject struct {
+	Timestamp int64           `xml:"timestamp,attr"`
+	Name      string          `xml:"name,attr"`
+	Metrics   CloverMetrics   `xml:"metrics"`
+	Packages  []CloverPackage `xml:"package"`
+}
+
+type CloverPackage struct {
+	Name    string        `xml:"name,attr"`
+	Metrics CloverMetrics `xml:"metrics"`
+	Files   []CloverFile  `xml:"file"`
+}
+
+type CloverFile struct {
+	Name    string        `xml:"name,attr"`
+	Path    string        `xml:"path,attr"`
+	Metrics CloverMetrics `xml:"metrics"`
+	Lines   []CloverLine  `xml:"line"`
+}
+
+type CloverMetrics struct {
+	Packages            int `xml:"packages,attr,omitempty"`
+	Files               int `xml:"files,attr,omitempty"`
+	Statements          int `xml:"statements,attr"`
+	CoveredStatements   int `xml:"coveredstatements,attr"`
+	Conditionals        int `xml:"conditionals,attr"`
+	CoveredConditionals int `xml:"coveredconditionals,attr"`
+	Methods             int `xml:"methods,attr"`
+	CoveredMethods      int `xml:"coveredmethods,attr"`
+	Elements            int `xml:"elements,attr"`
+	CoveredElements     int `xml:"coveredelements,attr"`
+}
+
+type CloverLine struct {
+	Num        int    `xml:"num,attr"`
+	Type       string `xml:"type,attr"`
+	Name       string `xml:"name,attr,omitempty"`
+	Visibility string `xml:"visibility,attr,omitempty"`
+	Count      int    `xml:"count,attr"`
+}
+
+func FormatClover(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	now := time.Now().UnixMilli()
+	model := CloverCoverage{
+		Generated: now,
+		Clover:    "4.4.1",
+		Project: CloverProject{
+			Timestamp: now,
+			Name:      context.Config.ProjectName,
+			Packages:  make([]CloverPackage, 0),
+		},
+	}
+
+	rootFiles := context.GetRootFiles()
+	if len(rootFiles) > 0 {
+		err = model.Project.addPackage(path.Base(context.Meta.CommonRoot), rootFiles)
+		if err != nil {
+			return err
+		}
+	}
+	for _, folder := range context.GetAllFolders() {
+		if len(folder.ReportedFiles) > 0 {
+			err = model.Project.addPackage(folder.GetRelPath(), folder.ReportedFiles)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	file.WriteString(xml.Header)
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	return encoder.Encode(model)
+}
+
+// addPackage adds a package of files and rolls the metrics up into the project.
+func (cp *CloverProject) addPackage(name string, files []*lib.ReportedFile) error {
+	pkg := CloverPackage{
+		Name:  name,
+		Files: make([]CloverFile, 0, len(files)),
+	}
+
+	for _, rptFile := range files {
+		cloverFile, err := newCloverFile(rptFile)
+		if err != nil {
+			return err
+		}
+		pkg.Files = append(pkg.Files, cloverFile)
+		pkg.Metrics.add(cloverFile.Metrics)
+	}
+	pkg.Metrics.Files = len(pkg.Files)
+
+	cp.Packages = append(cp.Packages, pkg)
+	cp.Metrics.add(pkg.Metrics)
+	cp.Metrics.Packages++
+	cp.Metrics.Files += pkg.Metrics.Files
+	return nil
+}
+
+func newCloverFile(rptFile *lib.ReportedFile) (CloverFile, error) {
+	absPath, err := filepath.Abs(rptFile.SourceFile)
+	if err != nil {
+		return CloverFile{}, lib.UnresolvablePathError(rptFile.SourceFile)
+	}
+	funcs, err := rptFile.GetFunctions()
+	if err != nil {
+		return CloverFile{}, err
+	}
+
+	cloverFile := CloverFile{
+		Name:  rptFile.GetRelPath(),
+		Path:  absPath,
+		Lines: make([]CloverLine, 0),
+	}
+	cloverFile.Metrics.Statements, cloverFile.Metrics.CoveredStatements = lib.GetStatementCounts(rptFile.Profile.Blocks)
+	cloverFile.Metrics.Methods = len(funcs)
+
+	// Clover expects the lines in order, so interleave the methods with the statement lines.
+	lineHits := lib.GetLineHits(rptFile.Profile.Blocks)
+	for _, fn := range funcs {
+		for len(lineHits) > 0 && lineHits[0].Line < fn.StartLine {
+			cloverFile.Lines = append(cloverFile.Lines, newCloverStmtLine(lineHits[0]))
+			lineHits = lineHits[1:]
+		}
+		visibility := "private"
+		if fn.IsExported() {
+			visibility = "public"
+		}
+		cloverFile.Lines = append(cloverFile.Lines, CloverLine{
+			Num:        fn.StartLine,
+			Type:       "method",
+			Name:       fn.Name,
+			Visibility: visibility,
+			Count:      fn.Hits,
+		})
+		if fn.Hits > 0 {
+			cloverFile.Metrics.CoveredMethods++
+		}
+	}
+	for _, hit := range lineHits {
+		cloverFile.Lines = append(cloverFile.Lines, newCloverStmtLine(hit))
+	}
+
+	cloverFile.Metrics.Elements = cloverFile.Metrics.Statements + cloverFile.Metrics.Methods
+	cloverFile.Metrics.CoveredElements = cloverFile.Metrics.CoveredStatements + cloverFile.Metrics.CoveredMethods
+	return cloverFile, nil
+}
+
+func newCloverStmtLine(hit lib.LineHits) CloverLine {
+	return CloverLine{Num: hit.Line, Type: "stmt", Count: hit.Hits}
+}
+
+// add rolls the statement, conditional, method, and element counts of other into the metrics.
+func (cm *CloverMetrics) add(other CloverMetrics) {
+	cm.Statements += other.Statements
+	cm.CoveredStatements += other.CoveredStatements
+	cm.Conditionals += other.Conditionals
+	cm.CoveredConditionals += other.CoveredConditionals
+	cm.Methods += other.Methods
+	cm.CoveredMethods += other.CoveredMethods
+	cm.Elements += other.Elements
+	cm.CoveredElements += other.CoveredElements
+}
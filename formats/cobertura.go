@@ -0,0 +1,140 @@
+package formats
+
+import (
+	"encoding/xml"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+const coberturaDocType = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">` + "\n"
+
+type CoberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      string             `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []CoberturaPackage `xml:"packages>package"`
+}
+
+type CoberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity string           `xml:"complexity,attr"`
+	Classes    []CoberturaClass `xml:"classes>class"`
+}
+
+type CoberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	FileName   string            `xml:"filename,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity string            `xml:"complexity,attr"`
+	Methods    []CoberturaMethod `xml:"methods>method"`
+	Lines      []CoberturaLine   `xml:"lines>line"`
+}
+
+type CoberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity string          `xml:"complexity,attr"`
+	Lines      []CoberturaLine `xml:"lines>line"`
+}
+
+type CoberturaLine struct {
+	Number int  `xml:"number,attr"`
+	Hits   int  `xml:"hits,attr"`
+	Branch bool `xml:"branch,attr"`
+}
+
+func FormatCobertura(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	model := CoberturaCoverage{
+		BranchRate: "0",
+		Complexity: "0",
+		Version:    "gocovrpt",
+		Timestamp:  time.Now().UnixMilli(),
+		Sources:    []string{context.Meta.CommonRoot},
+		Packages:   make([]CoberturaPackage, 0),
+	}
+
+	rootFiles := context.GetRootFiles()
+	if len(rootFiles) > 0 {
+		model.addPackage(".", rootFiles)
+	}
+	for _, folder := range context.GetAllFolders() {
+		if len(folder.ReportedFiles) > 0 {
+			model.addPackage(folder.GetRelPath(), folder.ReportedFiles)
+		}
+	}
+	model.LineRate = getCoberturaRate(model.LinesCovered, model.LinesValid)
+
+	file.WriteString(xml.Header)
+	file.WriteString(coberturaDocType)
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	return encoder.Encode(model)
+}
+
+// addPackage adds a package of classes, one per file, and rolls the line counts up into the totals.
+func (cc *CoberturaCoverage) addPackage(name string, files []*lib.ReportedFile) {
+	pkg := CoberturaPackage{
+		Name:       name,
+		BranchRate: "0",
+		Complexity: "0",
+		Classes:    make([]CoberturaClass, 0, len(files)),
+	}
+
+	pkgCovered, pkgValid := 0, 0
+	for _, rptFile := range files {
+		lineHits := lib.GetLineHits(rptFile.Profile.Blocks)
+		covered := lib.GetCoveredLineCount(lineHits)
+		class := CoberturaClass{
+			Name:       lib.SwapFileExt(rptFile.GetRelPath(), ""),
+			FileName:   rptFile.GetRelPath(),
+			LineRate:   getCoberturaRate(covered, len(lineHits)),
+			BranchRate: "0",
+			Complexity: "0",
+			Methods:    make([]CoberturaMethod, 0),
+			Lines:      make([]CoberturaLine, 0, len(lineHits)),
+		}
+		for _, hit := range lineHits {
+			class.Lines = append(class.Lines, CoberturaLine{Number: hit.Line, Hits: hit.Hits})
+		}
+		pkg.Classes = append(pkg.Classes, class)
+		pkgCovered += covered
+		pkgValid += len(lineHits)
+	}
+
+	pkg.LineRate = getCoberturaRate(pkgCovered, pkgValid)
+	if pkg.Name == "." {
+		pkg.Name = path.Base(cc.Sources[0])
+	}
+	cc.Packages = append(cc.Packages, pkg)
+	cc.LinesCovered += pkgCovered
+	cc.LinesValid += pkgValid
+}
+
+func getCoberturaRate(covered, valid int) string {
+	if valid == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(valid), 'f', 4, 64)
+}
@@ -0,0 +1,70 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/giocirque/gocovrpt/lib"
+	"golang.org/x/tools/cover"
+)
+
+// CodecovModel is Codecov's custom coverage schema, file paths to line numbers to hits
+type CodecovModel struct {
+	Coverage map[string]map[string]interface{} `json:"coverage"`
+}
+
+type codecovLine struct {
+	hits          int
+	blocks        int
+	coveredBlocks int
+}
+
+func FormatCodecov(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	repoRoot := lib.GetRepoRoot(context.Meta.CommonRoot)
+	model := CodecovModel{Coverage: make(map[string]map[string]interface{})}
+	for _, rptFile := range context.ReportedFiles {
+		model.Coverage[lib.GetRelPath(repoRoot, rptFile.SourceFile)] = getCodecovLines(rptFile.Profile.Blocks)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(model)
+}
+
+// getCodecovLines maps each line to its hit count, or to a "covered/total" blocks string when only some of the blocks on it ran.
+func getCodecovLines(blocks []cover.ProfileBlock) map[string]interface{} {
+	lineMap := make(map[int]*codecovLine)
+	for _, b := range blocks {
+		for line := b.StartLine; line <= b.EndLine; line++ {
+			entry, exists := lineMap[line]
+			if !exists {
+				entry = &codecovLine{}
+				lineMap[line] = entry
+			}
+			entry.blocks++
+			if b.Count > 0 {
+				entry.coveredBlocks++
+			}
+			if b.Count > entry.hits {
+				entry.hits = b.Count
+			}
+		}
+	}
+
+	lines := make(map[string]interface{}, len(lineMap))
+	for line, entry := range lineMap {
+		if entry.coveredBlocks > 0 && entry.coveredBlocks < entry.blocks {
+			lines[strconv.Itoa(line)] = fmt.Sprintf("%d/%d", entry.coveredBlocks, entry.blocks)
+		} else {
+			lines[strconv.Itoa(line)] = entry.hits
+		}
+	}
+	return lines
+}
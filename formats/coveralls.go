@@ -0,0 +1,84 @@
+package formats
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+// CoverallsModel is the Coveralls job payload, minus the upload itself. The repo token is left for the uploader to add, so
+// the secret is never written to the report.
+type CoverallsModel struct {
+	ServiceName  string                `json:"service_name,omitempty"`
+	ServiceJobId string                `json:"service_job_id,omitempty"`
+	FlagName     string                `json:"flag_name,omitempty"`
+	Parallel     bool                  `json:"parallel,omitempty"`
+	SourceFiles  []CoverallsSourceFile `json:"source_files"`
+}
+
+type CoverallsSourceFile struct {
+	Name         string `json:"name"`
+	SourceDigest string `json:"source_digest"`
+	Coverage     []*int `json:"coverage"`
+}
+
+func FormatCoveralls(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	model := CoverallsModel{
+		ServiceName:  getFirstValue(context.Config.ServiceName, os.Getenv("COVERALLS_SERVICE_NAME")),
+		ServiceJobId: getFirstValue(context.Config.ServiceJobId, os.Getenv("COVERALLS_SERVICE_JOB_ID")),
+		FlagName:     os.Getenv("COVERALLS_FLAG_NAME"),
+		Parallel:     os.Getenv("COVERALLS_PARALLEL") == "true",
+		SourceFiles:  make([]CoverallsSourceFile, 0, len(context.ReportedFiles)),
+	}
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		model.ServiceName = getFirstValue(model.ServiceName, "github")
+		model.ServiceJobId = getFirstValue(model.ServiceJobId, os.Getenv("GITHUB_RUN_ID"))
+	}
+
+	repoRoot := lib.GetRepoRoot(context.Meta.CommonRoot)
+	for _, rptFile := range context.ReportedFiles {
+		sourceCode, err := rptFile.GetSourceCode()
+		if err != nil {
+			return err
+		}
+		digest := md5.Sum([]byte(sourceCode))
+
+		// Coveralls wants a slot for every line of the source, null where nothing is instrumented.
+		coverage := make([]*int, strings.Count(strings.TrimSuffix(sourceCode, "\n"), "\n")+1)
+		for _, hit := range lib.GetLineHits(rptFile.Profile.Blocks) {
+			if hit.Line > 0 && hit.Line <= len(coverage) {
+				hits := hit.Hits
+				coverage[hit.Line-1] = &hits
+			}
+		}
+
+		model.SourceFiles = append(model.SourceFiles, CoverallsSourceFile{
+			Name:         lib.GetRelPath(repoRoot, rptFile.SourceFile),
+			SourceDigest: hex.EncodeToString(digest[:]),
+			Coverage:     coverage,
+		})
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(model)
+}
+
+func getFirstValue(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package formats
+
+import (
+	"encoding/csv"
+	"strconv"
+
+	"github.com/giocirque/gocovrpt/lib"
+	"golang.org/x/tools/cover"
+)
+
+const (
+	CsvKindAll    = "all"
+	CsvKindFile   = "file"
+	CsvKindFolder = "folder"
+)
+
+var csvHeader = []string{"path", "kind", "statements", "covered_statements", "blocks", "covered_blocks", "covered_pct"}
+
+func FormatCsv(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write(csvHeader)
+	if context.Config.CsvKind != CsvKindFile {
+		for _, folder := range context.GetAllFolders() {
+			writer.Write(newCsvRecord(folder.GetRelPath(), CsvKindFolder, folder.GetProfileBlocks(), folder.CoveredPct))
+		}
+	}
+	if context.Config.CsvKind != CsvKindFolder {
+		for _, rptFile := range context.ReportedFiles {
+			writer.Write(newCsvRecord(rptFile.GetRelPath(), CsvKindFile, rptFile.Profile.Blocks, rptFile.CoveredPct))
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func newCsvRecord(relPath string, kind string, blocks []cover.ProfileBlock, coveredPct float64) []string {
+	statements, coveredStatements := lib.GetStatementCounts(blocks)
+	blockCount, coveredBlocks := lib.GetBlockCounts(blocks)
+	return []string{
+		relPath,
+		kind,
+		strconv.Itoa(statements),
+		strconv.Itoa(coveredStatements),
+		strconv.Itoa(blockCount),
+		strconv.Itoa(coveredBlocks),
+		strconv.FormatFloat(coveredPct, 'f', 2, 64),
+	}
+}
@@ -0,0 +1,162 @@
+package formats
+
+import (
+	"encoding/xml"
+	"math"
+	"path"
+
+	"github.com/giocirque/gocovrpt/lib"
+	"golang.org/x/tools/cover"
+)
+
+// The version of the report document schema, bumped whenever a field is renamed or removed.
+const DocumentVersion = 1
+
+// ReportDocument is the serializable form of a ReportContext used by the data formats
+type ReportDocument struct {
+	// The root element name for XML documents
+	XMLName xml.Name `json:"-" yaml:"-" xml:"report"`
+	// The version of the document schema
+	Version int `json:"version" yaml:"version" xml:"version"`
+	// The tool that generated the document
+	Generator string `json:"generator" yaml:"generator" xml:"generator"`
+	// The display name of the project
+	ProjectName string `json:"projectName" yaml:"projectName" xml:"projectName"`
+	// The source directory all paths are relative to
+	SourceDir string `json:"sourceDir" yaml:"sourceDir" xml:"sourceDir"`
+	// The coverage mode, hits are execution counts in count and atomic modes, and 0 or 1 in set mode
+	Mode string `json:"mode" yaml:"mode" xml:"mode"`
+	// The roll-up totals for the whole report
+	Totals DocumentTotals `json:"totals" yaml:"totals" xml:"totals"`
+	// The top-level folders of the report
+	Folders []DocumentFolder `json:"folders" yaml:"folders" xml:"folders>folder"`
+	// The files that sit directly in the source directory
+	Files []DocumentFile `json:"files" yaml:"files" xml:"files>file"`
+}
+
+// DocumentTotals are the roll-up counts for a node in the report
+type DocumentTotals struct {
+	// The number of statements
+	Statements int `json:"statements" yaml:"statements" xml:"statements"`
+	// The number of statements that were executed
+	CoveredStatements int `json:"coveredStatements" yaml:"coveredStatements" xml:"coveredStatements"`
+	// The number of blocks
+	Blocks int `json:"blocks" yaml:"blocks" xml:"blocks"`
+	// The number of blocks that were executed
+	CoveredBlocks int `json:"coveredBlocks" yaml:"coveredBlocks" xml:"coveredBlocks"`
+	// The coverage percentage
+	CoveredPct float64 `json:"coveredPct" yaml:"coveredPct" xml:"coveredPct"`
+}
+
+// DocumentFolder is a folder of files in the report document
+type DocumentFolder struct {
+	// The slash separated path relative to the source directory
+	Path string `json:"path" yaml:"path" xml:"path"`
+	// The name of the folder
+	Name string `json:"name" yaml:"name" xml:"name"`
+	// The roll-up totals for the folder and all sub-folders
+	Totals DocumentTotals `json:"totals" yaml:"totals" xml:"totals"`
+	// The sub-folders of the folder
+	Folders []DocumentFolder `json:"folders" yaml:"folders" xml:"folders>folder"`
+	// The files of the folder
+	Files []DocumentFile `json:"files" yaml:"files" xml:"files>file"`
+}
+
+// DocumentFile is a covered source file in the report document
+type DocumentFile struct {
+	// The slash separated path relative to the source directory
+	Path string `json:"path" yaml:"path" xml:"path"`
+	// The name of the file
+	Name string `json:"name" yaml:"name" xml:"name"`
+	// The totals for the file
+	Totals DocumentTotals `json:"totals" yaml:"totals" xml:"totals"`
+	// The profiled blocks of the file
+	Blocks []DocumentBlock `json:"blocks" yaml:"blocks" xml:"blocks>block"`
+}
+
+// DocumentBlock is a single profiled block of a source file
+type DocumentBlock struct {
+	// The start line number for this block
+	StartLine int `json:"startLine" yaml:"startLine" xml:"startLine"`
+	// The start column number for this block
+	StartCol int `json:"startCol" yaml:"startCol" xml:"startCol"`
+	// The stop line number for this block
+	StopLine int `json:"stopLine" yaml:"stopLine" xml:"stopLine"`
+	// The stop column number for this block, left out when the input only has lines
+	StopCol int `json:"stopCol,omitempty" yaml:"stopCol,omitempty" xml:"stopCol,omitempty"`
+	// The number of statements in this block
+	Statements int `json:"statements" yaml:"statements" xml:"statements"`
+	// The number of times this block was executed
+	Hits int `json:"hits" yaml:"hits" xml:"hits"`
+}
+
+// NewReportDocument builds a ReportDocument from the context.
+func NewReportDocument(context *lib.ReportContext) ReportDocument {
+	doc := ReportDocument{
+		Version:     DocumentVersion,
+		Generator:   "gocovrpt",
+		ProjectName: context.Config.ProjectName,
+		SourceDir:   context.Meta.CommonRoot,
+		Mode:        context.Meta.Mode,
+		Totals:      newDocumentTotals(context.GetProfileBlocks(), context.CoveredPct),
+		Folders:     make([]DocumentFolder, 0, len(context.ReportedFolders)),
+		Files:       make([]DocumentFile, 0),
+	}
+	for _, folder := range context.ReportedFolders {
+		doc.Folders = append(doc.Folders, newDocumentFolder(folder))
+	}
+	for _, file := range context.GetRootFiles() {
+		doc.Files = append(doc.Files, newDocumentFile(file))
+	}
+	return doc
+}
+
+func newDocumentFolder(folder *lib.ReportedFolder) DocumentFolder {
+	docFolder := DocumentFolder{
+		Path:    folder.GetRelPath(),
+		Name:    folder.FolderName,
+		Totals:  newDocumentTotals(folder.GetProfileBlocks(), folder.CoveredPct),
+		Folders: make([]DocumentFolder, 0, len(folder.ReportedFolders)),
+		Files:   make([]DocumentFile, 0, len(folder.ReportedFiles)),
+	}
+	for _, subFolder := range folder.ReportedFolders {
+		docFolder.Folders = append(docFolder.Folders, newDocumentFolder(subFolder))
+	}
+	for _, file := range folder.ReportedFiles {
+		docFolder.Files = append(docFolder.Files, newDocumentFile(file))
+	}
+	return docFolder
+}
+
+func newDocumentFile(file *lib.ReportedFile) DocumentFile {
+	relPath := file.GetRelPath()
+	docFile := DocumentFile{
+		Path:   relPath,
+		Name:   path.Base(relPath),
+		Totals: newDocumentTotals(file.Profile.Blocks, file.CoveredPct),
+		Blocks: make([]DocumentBlock, 0, len(file.Profile.Blocks)),
+	}
+	for _, b := range file.Profile.Blocks {
+		docFile.Blocks = append(docFile.Blocks, DocumentBlock{
+			StartLine:  b.StartLine,
+			StartCol:   b.StartCol,
+			StopLine:   b.EndLine,
+			StopCol:    lib.GetOutputEndCol(b.EndCol),
+			Statements: b.NumStmt,
+			Hits:       b.Count,
+		})
+	}
+	return docFile
+}
+
+func newDocumentTotals(blocks []cover.ProfileBlock, coveredPct float64) DocumentTotals {
+	statements, coveredStatements := lib.GetStatementCounts(blocks)
+	blockCount, coveredBlocks := lib.GetBlockCounts(blocks)
+	return DocumentTotals{
+		Statements:        statements,
+		CoveredStatements: coveredStatements,
+		Blocks:            blockCount,
+		CoveredBlocks:     coveredBlocks,
+		CoveredPct:        math.Round(coveredPct*100) / 100,
+	}
+}
@@ -0,0 +1,49 @@
+package formats
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+// FormatFunc writes the per-function coverage exactly as `go tool cover -func` does.
+func FormatFunc(context *lib.ReportContext) error {
+	out, _, err := getTextOutput(context)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	// The cover tool lists files in the order of the profile file names, not the resolved paths.
+	files := make([]*lib.ReportedFile, len(context.ReportedFiles))
+	copy(files, context.ReportedFiles)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Profile.FileName < files[j].Profile.FileName
+	})
+
+	tabber := tabwriter.NewWriter(out, 1, 8, 1, '\t', 0)
+	total, covered := 0, 0
+	for _, rptFile := range files {
+		funcs, err := rptFile.GetFunctions()
+		if err != nil {
+			return err
+		}
+		for _, fn := range funcs {
+			fmt.Fprintf(tabber, "%s:%d:\t%s\t%.1f%%\n", rptFile.Profile.FileName, fn.StartLine, fn.Name, fn.GetCoveredPct(true))
+			total += fn.Statements
+			covered += fn.CoveredStatements
+		}
+	}
+	totalPct := 0.0
+	if total > 0 {
+		totalPct = 100 * float64(covered) / float64(total)
+	}
+	fmt.Fprintf(tabber, "total:\t(statements)\t%.1f%%\n", totalPct)
+
+	return tabber.Flush()
+}
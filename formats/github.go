@@ -0,0 +1,88 @@
+package formats
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+// FormatGithub writes workflow command annotations for the uncovered blocks, and a job summary when running in Actions.
+func FormatGithub(context *lib.ReportContext) error {
+	out, _, err := getTextOutput(context)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	// Annotations must be relative to the checkout for GitHub to place them in the diff.
+	repoRoot := os.Getenv("GITHUB_WORKSPACE")
+	if repoRoot == "" {
+		repoRoot = lib.GetRepoRoot(context.Meta.CommonRoot)
+	}
+
+	written, skipped := 0, 0
+	maxAnnotations := context.Config.MaxAnnotations
+	for _, rptFile := range context.ReportedFiles {
+		filePath := lib.GetRelPath(repoRoot, rptFile.SourceFile)
+		for _, block := range mergeUncoveredBlocks(rptFile.ReportedLines) {
+			if maxAnnotations > 0 && written >= maxAnnotations {
+				skipped++
+				continue
+			}
+			fmt.Fprintf(out, "::warning file=%s,line=%d,endLine=%d,title=%s::%s\n",
+				escapeGithubProperty(filePath), block.StartLine, block.StopLine,
+				escapeGithubProperty("Uncovered code"), escapeGithubData("Uncovered block"))
+			written++
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(out, "::notice::%s\n", escapeGithubData(fmt.Sprintf("%d more uncovered blocks were not annotated", skipped)))
+	}
+
+	summaryPath := os.Getenv("GITHUB_STEP_SUMMARY")
+	if summaryPath == "" {
+		return nil
+	}
+	summary, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer summary.Close()
+	return writeMarkdown(summary, context)
+}
+
+// mergeUncoveredBlocks returns the uncovered blocks, with blocks on touching or adjacent lines merged into one.
+func mergeUncoveredBlocks(blocks []lib.ReportedBlock) []lib.ReportedBlock {
+	merged := make([]lib.ReportedBlock, 0)
+	for _, block := range blocks {
+		if block.Covered {
+			continue
+		}
+		last := len(merged) - 1
+		if last >= 0 && block.StartLine <= merged[last].StopLine+1 {
+			if block.StopLine > merged[last].StopLine {
+				merged[last].StopLine = block.StopLine
+				merged[last].StopCol = block.StopCol
+			}
+			continue
+		}
+		merged = append(merged, block)
+	}
+	return merged
+}
+
+func escapeGithubData(value string) string {
+	value = strings.ReplaceAll(value, "%", "%25")
+	value = strings.ReplaceAll(value, "\r", "%0D")
+	return strings.ReplaceAll(value, "\n", "%0A")
+}
+
+func escapeGithubProperty(value string) string {
+	value = escapeGithubData(value)
+	value = strings.ReplaceAll(value, ":", "%3A")
+	return strings.ReplaceAll(value, ",", "%2C")
+}
@@ -52,6 +52,9 @@ func FormatHtml(context *lib.ReportContext) error {
 			}
 			return sourceCode
 		},
+		"lineHits": func(a lib.ReportedFile) []lib.LineHits {
+			return lib.GetLineHits(a.Profile.Blocks)
+		},
 		"swapExt": func(value string, ext string) string {
 			return lib.SwapFileExt(value, ext)
 		},
@@ -0,0 +1,173 @@
+package formats
+
+import (
+	"encoding/xml"
+	"path"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+const jacocoDocType = `<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">` + "\n"
+
+const (
+	jacocoCounterInstruction = "INSTRUCTION"
+	jacocoCounterLine        = "LINE"
+	jacocoCounterMethod      = "METHOD"
+)
+
+type JacocoReport struct {
+	XMLName  xml.Name        `xml:"report"`
+	Name     string          `xml:"name,attr"`
+	Packages []JacocoPackage `xml:"package"`
+	Counters []JacocoCounter `xml:"counter"`
+}
+
+type JacocoPackage struct {
+	Name        string             `xml:"name,attr"`
+	SourceFiles []JacocoSourceFile `xml:"sourcefile"`
+	Counters    []JacocoCounter    `xml:"counter"`
+}
+
+type JacocoSourceFile struct {
+	Name     string          `xml:"name,attr"`
+	Lines    []JacocoLine    `xml:"line"`
+	Counters []JacocoCounter `xml:"counter"`
+}
+
+type JacocoLine struct {
+	Number              int `xml:"nr,attr"`
+	MissedInstructions  int `xml:"mi,attr"`
+	CoveredInstructions int `xml:"ci,attr"`
+	MissedBranches      int `xml:"mb,attr"`
+	CoveredBranches     int `xml:"cb,attr"`
+}
+
+type JacocoCounter struct {
+	Type    string `xml:"type,attr"`
+	Missed  int    `xml:"missed,attr"`
+	Covered int    `xml:"covered,attr"`
+}
+
+func FormatJacoco(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	model := JacocoReport{
+		Name:     context.Config.ProjectName,
+		Packages: make([]JacocoPackage, 0),
+	}
+
+	rootFiles := context.GetRootFiles()
+	if len(rootFiles) > 0 {
+		// Files in the source directory belong to JaCoCo's default package, which has no name.
+		err = model.addPackage("", rootFiles)
+		if err != nil {
+			return err
+		}
+	}
+	for _, folder := range context.GetAllFolders() {
+		if len(folder.ReportedFiles) > 0 {
+			err = model.addPackage(folder.GetRelPath(), folder.ReportedFiles)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	file.WriteString(xml.Header)
+	file.WriteString(jacocoDocType)
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	return encoder.Encode(model)
+}
+
+// addPackage adds a package of source files and rolls the counters up into the report.
+func (jr *JacocoReport) addPackage(name string, files []*lib.ReportedFile) error {
+	pkg := JacocoPackage{
+		Name:        name,
+		SourceFiles: make([]JacocoSourceFile, 0, len(files)),
+	}
+
+	for _, rptFile := range files {
+		sourceFile, err := newJacocoSourceFile(rptFile)
+		if err != nil {
+			return err
+		}
+		pkg.SourceFiles = append(pkg.SourceFiles, sourceFile)
+		pkg.Counters = addJacocoCounters(pkg.Counters, sourceFile.Counters)
+	}
+
+	jr.Packages = append(jr.Packages, pkg)
+	jr.Counters = addJacocoCounters(jr.Counters, pkg.Counters)
+	return nil
+}
+
+func newJacocoSourceFile(rptFile *lib.ReportedFile) (JacocoSourceFile, error) {
+	funcs, err := rptFile.GetFunctions()
+	if err != nil {
+		return JacocoSourceFile{}, err
+	}
+
+	sourceFile := JacocoSourceFile{
+		Name:  path.Base(rptFile.GetRelPath()),
+		Lines: make([]JacocoLine, 0, len(rptFile.Profile.Blocks)),
+	}
+	// The statements of each block are counted on the line it starts on, so the lines add up to the instruction counter.
+	// The blocks are sorted, so blocks starting on the same line are next to each other.
+	coveredLines := 0
+	for _, b := range rptFile.Profile.Blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+		last := len(sourceFile.Lines) - 1
+		if last < 0 || sourceFile.Lines[last].Number != b.StartLine {
+			sourceFile.Lines = append(sourceFile.Lines, JacocoLine{Number: b.StartLine})
+			last++
+		}
+		line := &sourceFile.Lines[last]
+		if b.Count > 0 {
+			if line.CoveredInstructions == 0 {
+				coveredLines++
+			}
+			line.CoveredInstructions += b.NumStmt
+		} else {
+			line.MissedInstructions += b.NumStmt
+		}
+	}
+
+	statements, coveredStatements := lib.GetStatementCounts(rptFile.Profile.Blocks)
+	coveredFuncs := 0
+	for _, fn := range funcs {
+		if fn.Hits > 0 {
+			coveredFuncs++
+		}
+	}
+	sourceFile.Counters = []JacocoCounter{
+		{Type: jacocoCounterInstruction, Missed: statements - coveredStatements, Covered: coveredStatements},
+		{Type: jacocoCounterLine, Missed: len(sourceFile.Lines) - coveredLines, Covered: coveredLines},
+		{Type: jacocoCounterMethod, Missed: len(funcs) - coveredFuncs, Covered: coveredFuncs},
+	}
+	return sourceFile, nil
+}
+
+// addJacocoCounters sums the counters of other into counters, matching them by type.
+func addJacocoCounters(counters []JacocoCounter, other []JacocoCounter) []JacocoCounter {
+	for _, o := range other {
+		found := false
+		for i := range counters {
+			if counters[i].Type == o.Type {
+				counters[i].Missed += o.Missed
+				counters[i].Covered += o.Covered
+				found = true
+				break
+			}
+		}
+		if !found {
+			counters = append(counters, o)
+		}
+	}
+	return counters
+}
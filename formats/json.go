@@ -0,0 +1,19 @@
+package formats
+
+import (
+	"encoding/json"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+func FormatJson(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewReportDocument(context))
+}
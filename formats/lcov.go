@@ -0,0 +1,37 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+func FormatLcov(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, rptFile := range context.ReportedFiles {
+		sourceFile, err := filepath.Abs(rptFile.SourceFile)
+		if err != nil {
+			return lib.UnresolvablePathError(rptFile.SourceFile)
+		}
+
+		lineHits := lib.GetLineHits(rptFile.Profile.Blocks)
+		fmt.Fprintf(writer, "TN:%s\n", context.Config.ProjectName)
+		fmt.Fprintf(writer, "SF:%s\n", sourceFile)
+		for _, hit := range lineHits {
+			fmt.Fprintf(writer, "DA:%d,%d\n", hit.Line, hit.Hits)
+		}
+		fmt.Fprintf(writer, "LF:%d\n", len(lineHits))
+		fmt.Fprintf(writer, "LH:%d\n", lib.GetCoveredLineCount(lineHits))
+		fmt.Fprintln(writer, "end_of_record")
+	}
+
+	return writer.Flush()
+}
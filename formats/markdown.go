@@ -0,0 +1,110 @@
+package formats
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/giocirque/gocovrpt/lib"
+	"golang.org/x/tools/cover"
+)
+
+type MarkdownModel struct {
+	ProjectName  string
+	Total        MarkdownRow
+	Rows         []MarkdownRow
+	LeastCovered []MarkdownRow
+}
+
+type MarkdownRow struct {
+	Name              string
+	Indent            string
+	Indicator         string
+	CoveredPct        float64
+	Statements        int
+	CoveredStatements int
+}
+
+func FormatMarkdown(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return writeMarkdown(file, context)
+}
+
+// writeMarkdown renders the markdown summary of the context to the writer.
+func writeMarkdown(writer io.Writer, context *lib.ReportContext) error {
+	templ, err := template.ParseFS(templates, "templates/*.gomd")
+	if err != nil {
+		return err
+	}
+
+	rootFolder := context.GetPseudoFolder()
+
+	model := MarkdownModel{
+		ProjectName:  context.Config.ProjectName,
+		Total:        newMarkdownRow("", context.GetProfileBlocks(), context.CoveredPct),
+		Rows:         make([]MarkdownRow, 0),
+		LeastCovered: make([]MarkdownRow, 0),
+	}
+	// The files in the root get a row of their own, so the top level rows add up to the total.
+	if rootFiles := context.GetRootFiles(); len(rootFiles) > 0 {
+		blocks := make([]cover.ProfileBlock, 0)
+		for _, file := range rootFiles {
+			blocks = append(blocks, file.Profile.Blocks...)
+		}
+		model.Rows = append(model.Rows, newMarkdownRow(".", blocks, lib.GetCoveredPct(blocks, true)))
+	}
+	model.Rows = appendMarkdownFolders(model.Rows, rootFolder.ReportedFolders, 0)
+
+	if context.Config.LeastCovered > 0 {
+		files := make([]*lib.ReportedFile, len(context.ReportedFiles))
+		copy(files, context.ReportedFiles)
+		sort.SliceStable(files, func(i, j int) bool {
+			if files[i].CoveredPct == files[j].CoveredPct {
+				return files[i].GetRelPath() < files[j].GetRelPath()
+			}
+			return files[i].CoveredPct < files[j].CoveredPct
+		})
+		for i := 0; i < len(files) && i < context.Config.LeastCovered; i++ {
+			model.LeastCovered = append(model.LeastCovered, newMarkdownRow(files[i].GetRelPath(), files[i].Profile.Blocks, files[i].CoveredPct))
+		}
+	}
+
+	return templ.ExecuteTemplate(writer, "summary.gomd", model)
+}
+
+// appendMarkdownFolders appends a row for each folder followed by the rows of its subfolders, indented a level deeper.
+func appendMarkdownFolders(rows []MarkdownRow, folders []*lib.ReportedFolder, depth int) []MarkdownRow {
+	for _, folder := range folders {
+		row := newMarkdownRow(folder.FolderName+"/", folder.GetProfileBlocks(), folder.CoveredPct)
+		row.Indent = strings.Repeat("&nbsp;&nbsp;&nbsp;&nbsp;", depth)
+		rows = appendMarkdownFolders(append(rows, row), folder.ReportedFolders, depth+1)
+	}
+	return rows
+}
+
+func newMarkdownRow(name string, blocks []cover.ProfileBlock, coveredPct float64) MarkdownRow {
+	statements, coveredStatements := lib.GetStatementCounts(blocks)
+	return MarkdownRow{
+		Name:              name,
+		Indicator:         getCoverageIndicator(coveredPct),
+		CoveredPct:        coveredPct,
+		Statements:        statements,
+		CoveredStatements: coveredStatements,
+	}
+}
+
+func getCoverageIndicator(percent float64) string {
+	indicator := "🔴"
+	if percent >= 80 {
+		indicator = "🟢"
+	} else if percent >= 50 {
+		indicator = "🟡"
+	}
+	return indicator
+}
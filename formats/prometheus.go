@@ -0,0 +1,76 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/giocirque/gocovrpt/lib"
+	"golang.org/x/tools/cover"
+)
+
+type prometheusNode struct {
+	folder     string
+	coveredPct float64
+	blocks     []cover.ProfileBlock
+}
+
+// FormatPrometheus writes gauges for every node of the folder tree in the Prometheus text exposition format.
+func FormatPrometheus(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rootFolder := context.GetPseudoFolder()
+	nodes := []prometheusNode{{folder: ".", coveredPct: context.CoveredPct, blocks: context.GetProfileBlocks()}}
+	for _, folder := range rootFolder.GetAllFolders() {
+		nodes = append(nodes, prometheusNode{folder: folder.GetRelPath(), coveredPct: folder.CoveredPct, blocks: folder.GetProfileBlocks()})
+	}
+
+	project := escapePrometheusLabel(context.Config.ProjectName)
+	writer := bufio.NewWriter(file)
+
+	writePrometheusHeader(writer, "gocovrpt_coverage_ratio", "The ratio of covered code, from 0 to 1.")
+	for _, node := range nodes {
+		labels := fmt.Sprintf(`project="%s",folder="%s"`, project, escapePrometheusLabel(node.folder))
+		writePrometheusSample(writer, "gocovrpt_coverage_ratio", labels, node.coveredPct/100)
+	}
+
+	writePrometheusHeader(writer, "gocovrpt_statements_total", "The number of statements by coverage state.")
+	for _, node := range nodes {
+		total, covered := lib.GetStatementCounts(node.blocks)
+		writePrometheusStates(writer, "gocovrpt_statements_total", project, node.folder, total, covered)
+	}
+
+	writePrometheusHeader(writer, "gocovrpt_blocks_total", "The number of blocks by coverage state.")
+	for _, node := range nodes {
+		total, covered := lib.GetBlockCounts(node.blocks)
+		writePrometheusStates(writer, "gocovrpt_blocks_total", project, node.folder, total, covered)
+	}
+
+	return writer.Flush()
+}
+
+func writePrometheusHeader(writer *bufio.Writer, name string, help string) {
+	fmt.Fprintf(writer, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(writer, "# TYPE %s gauge\n", name)
+}
+
+func writePrometheusStates(writer *bufio.Writer, name string, project string, folder string, total int, covered int) {
+	labels := fmt.Sprintf(`project="%s",folder="%s"`, project, escapePrometheusLabel(folder))
+	writePrometheusSample(writer, name, labels+`,state="covered"`, float64(covered))
+	writePrometheusSample(writer, name, labels+`,state="uncovered"`, float64(total-covered))
+}
+
+func writePrometheusSample(writer *bufio.Writer, name string, labels string, value float64) {
+	fmt.Fprintf(writer, "%s{%s} %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func escapePrometheusLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
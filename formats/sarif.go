@@ -0,0 +1,239 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifBaseId  = "SRCROOT"
+	// The unit of the region columns, which are converted from the byte columns of the profiles
+	sarifColumnKind = "utf16CodeUnits"
+)
+
+// The SARIF rules, one for each kind of finding.
+var sarifRules = []SarifRule{
+	{
+		Id:               "uncovered-function",
+		Name:             "UncoveredFunction",
+		ShortDescription: SarifMessage{Text: "Function is never executed by tests"},
+		Configuration:    SarifConfiguration{Level: "warning"},
+	},
+	{
+		Id:               "low-coverage-function",
+		Name:             "LowCoverageFunction",
+		ShortDescription: SarifMessage{Text: "Function coverage is below the threshold"},
+		Configuration:    SarifConfiguration{Level: "note"},
+	},
+	{
+		Id:               "uncovered-block",
+		Name:             "UncoveredBlock",
+		ShortDescription: SarifMessage{Text: "Block is never executed by tests"},
+		Configuration:    SarifConfiguration{Level: "note"},
+	},
+}
+
+const (
+	sarifRuleUncoveredFunction = iota
+	sarifRuleLowCoverageFunction
+	sarifRuleUncoveredBlock
+)
+
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+type SarifRun struct {
+	Tool               SarifTool                        `json:"tool"`
+	OriginalUriBaseIds map[string]SarifArtifactLocation `json:"originalUriBaseIds"`
+	ColumnKind         string                           `json:"columnKind"`
+	Results            []SarifResult                    `json:"results"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+type SarifRule struct {
+	Id               string             `json:"id"`
+	Name             string             `json:"name"`
+	ShortDescription SarifMessage       `json:"shortDescription"`
+	Configuration    SarifConfiguration `json:"defaultConfiguration"`
+}
+
+type SarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations"`
+}
+
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           SarifRegion           `json:"region"`
+}
+
+type SarifArtifactLocation struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId,omitempty"`
+}
+
+type SarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func FormatSarif(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Code scanning resolves locations against the repository, so that's the base for all paths.
+	repoRoot := lib.GetRepoRoot(context.Meta.CommonRoot)
+	run := SarifRun{
+		Tool: SarifTool{Driver: SarifDriver{
+			Name:           "gocovrpt",
+			InformationUri: "https://github.com/giocirque/gocovrpt",
+			Rules:          sarifRules,
+		}},
+		OriginalUriBaseIds: map[string]SarifArtifactLocation{
+			sarifBaseId: {Uri: (&url.URL{Scheme: "file", Path: filepath.ToSlash(repoRoot) + "/"}).String()},
+		},
+		ColumnKind: sarifColumnKind,
+		Results:    make([]SarifResult, 0),
+	}
+
+	for _, rptFile := range context.ReportedFiles {
+		funcs, err := rptFile.GetFunctions()
+		if err != nil {
+			return err
+		}
+		sourceCode, err := rptFile.GetSourceCode()
+		if err != nil {
+			return err
+		}
+		sourceLines := strings.Split(sourceCode, "\n")
+		fileUri := getSarifUri(lib.GetRelPath(repoRoot, rptFile.SourceFile))
+
+		uncoveredFuncs := make([]lib.ReportedFunc, 0)
+		for _, fn := range funcs {
+			region := newSarifRegion(sourceLines, fn.StartLine, fn.StartCol, fn.StopLine, fn.StopCol)
+			if fn.Statements > 0 && fn.CoveredStatements == 0 {
+				uncoveredFuncs = append(uncoveredFuncs, fn)
+				message := fmt.Sprintf("Function %s is not covered by tests.", fn.Name)
+				run.addResult(sarifRuleUncoveredFunction, message, fileUri, region)
+			} else if fn.Statements > 0 && fn.GetCoveredPct(true) < context.Config.Threshold {
+				message := fmt.Sprintf("Function %s is %.1f%% covered, below the %.1f%% threshold.", fn.Name, fn.GetCoveredPct(true), context.Config.Threshold)
+				run.addResult(sarifRuleLowCoverageFunction, message, fileUri, region)
+			}
+		}
+
+		for _, block := range rptFile.ReportedLines {
+			if block.Covered || isBlockInFuncs(block, uncoveredFuncs) {
+				// Blocks of uncovered functions are already reported with the function.
+				continue
+			}
+			region := newSarifRegion(sourceLines, block.StartLine, block.StartCol, block.StopLine, lib.GetOutputEndCol(block.StopCol))
+			run.addResult(sarifRuleUncoveredBlock, "Block is not covered by tests.", fileUri, region)
+		}
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(SarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SarifRun{run},
+	})
+}
+
+// addResult adds a finding for the rule at the region of the file.
+func (sr *SarifRun) addResult(ruleIndex int, message string, fileUri string, region SarifRegion) {
+	rule := sarifRules[ruleIndex]
+	sr.Results = append(sr.Results, SarifResult{
+		RuleId:    rule.Id,
+		RuleIndex: ruleIndex,
+		Level:     rule.Configuration.Level,
+		Message:   SarifMessage{Text: message},
+		Locations: []SarifLocation{{
+			PhysicalLocation: SarifPhysicalLocation{
+				ArtifactLocation: SarifArtifactLocation{Uri: fileUri, UriBaseId: sarifBaseId},
+				Region:           region,
+			},
+		}},
+	})
+}
+
+func isBlockInFuncs(block lib.ReportedBlock, funcs []lib.ReportedFunc) bool {
+	for _, fn := range funcs {
+		if block.StartLine >= fn.StartLine && block.StopLine <= fn.StopLine {
+			return true
+		}
+	}
+	return false
+}
+
+// newSarifRegion makes the region between the byte columns of the source lines, in UTF-16 code units.
+func newSarifRegion(sourceLines []string, startLine int, startCol int, endLine int, endCol int) SarifRegion {
+	return SarifRegion{
+		StartLine:   startLine,
+		StartColumn: getSarifColumn(sourceLines, startLine, startCol),
+		EndLine:     endLine,
+		EndColumn:   getSarifColumn(sourceLines, endLine, endCol),
+	}
+}
+
+// getSarifColumn converts a 1 based byte column of the line to UTF-16 code units. A column of 0 is left out and stays 0.
+func getSarifColumn(sourceLines []string, line int, col int) int {
+	if col < 1 || line < 1 || line > len(sourceLines) {
+		return col
+	}
+	text := sourceLines[line-1]
+	if col-1 > len(text) {
+		// Past the end of the line, which is only the end of a block on the last line
+		return len(utf16.Encode([]rune(text))) + col - len(text)
+	}
+	return len(utf16.Encode([]rune(text[:col-1]))) + 1
+}
+
+// getSarifUri escapes each segment of the slash separated path for a URI reference.
+func getSarifUri(relPath string) string {
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
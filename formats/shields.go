@@ -0,0 +1,35 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+// ShieldsModel is the shields.io endpoint badge schema
+type ShieldsModel struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+func FormatShields(context *lib.ReportContext) error {
+	value := math.RoundToEven(context.GetPseudoFolder().CoveredPct)
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	model := ShieldsModel{
+		SchemaVersion: 1,
+		Label:         "coverage",
+		Message:       fmt.Sprintf("%.0f%%", value),
+		Color:         strings.TrimPrefix(getCoverageColor(value), "#"),
+	}
+	return json.NewEncoder(file).Encode(model)
+}
@@ -0,0 +1,52 @@
+package formats
+
+import (
+	"encoding/xml"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+type SonarCoverage struct {
+	XMLName xml.Name    `xml:"coverage"`
+	Version int         `xml:"version,attr"`
+	Files   []SonarFile `xml:"file"`
+}
+
+type SonarFile struct {
+	Path  string      `xml:"path,attr"`
+	Lines []SonarLine `xml:"lineToCover"`
+}
+
+type SonarLine struct {
+	LineNumber int  `xml:"lineNumber,attr"`
+	Covered    bool `xml:"covered,attr"`
+}
+
+func FormatSonar(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	model := SonarCoverage{
+		Version: 1,
+		Files:   make([]SonarFile, 0, len(context.ReportedFiles)),
+	}
+	for _, rptFile := range context.ReportedFiles {
+		// Sonar matches files by their path relative to the project base directory, which is --source.
+		lineHits := lib.GetLineHits(rptFile.Profile.Blocks)
+		sonarFile := SonarFile{
+			Path:  rptFile.GetRelPath(),
+			Lines: make([]SonarLine, 0, len(lineHits)),
+		}
+		for _, hit := range lineHits {
+			sonarFile.Lines = append(sonarFile.Lines, SonarLine{LineNumber: hit.Line, Covered: hit.Hits > 0})
+		}
+		model.Files = append(model.Files, sonarFile)
+	}
+
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	return encoder.Encode(model)
+}
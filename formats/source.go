@@ -0,0 +1,131 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+const (
+	sourceLineUntracked = iota
+	sourceLineCovered
+	sourceLineUncovered
+	sourceLinePartial
+)
+
+// The gutter markers for each line state.
+var sourceMarkers = []string{" ", "+", "-", "~"}
+
+// FormatSource writes each file's source with line numbers and a gutter marker showing its coverage.
+func FormatSource(context *lib.ReportContext) error {
+	out, useColor, err := getTextOutput(context)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	writer := bufio.NewWriter(out)
+	wroteFile := false
+	for _, rptFile := range context.ReportedFiles {
+		sourceCode, err := rptFile.GetSourceCode()
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(strings.TrimSuffix(sourceCode, "\n"), "\n")
+		states := getSourceLineStates(rptFile, len(lines))
+		visible := getVisibleSourceLines(states, context.Config.Context)
+		if visible == nil {
+			continue
+		}
+
+		if wroteFile {
+			fmt.Fprintln(writer)
+		}
+		wroteFile = true
+		header := fmt.Sprintf("%s  %.2f%%", rptFile.GetRelPath(), rptFile.CoveredPct)
+		if useColor {
+			header = getCoverageAnsiColor(rptFile.CoveredPct) + header + ansiReset
+		}
+		fmt.Fprintln(writer, header)
+
+		width := len(fmt.Sprint(len(lines)))
+		lastShown := 0
+		for n, line := range lines {
+			lineNum := n + 1
+			if !visible[lineNum] {
+				continue
+			}
+			if lastShown > 0 && lineNum > lastShown+1 {
+				fmt.Fprintf(writer, "%*s ⋮\n", width, "")
+			}
+			lastShown = lineNum
+			writeSourceLine(writer, lineNum, width, states[lineNum], line, useColor)
+		}
+	}
+
+	return writer.Flush()
+}
+
+// getSourceLineStates returns the state of every line, indexed by line number.
+func getSourceLineStates(rptFile *lib.ReportedFile, lineCount int) []int {
+	states := make([]int, lineCount+1)
+	for _, hit := range lib.GetLineHits(rptFile.Profile.Blocks) {
+		if hit.Line < 1 || hit.Line > lineCount {
+			continue
+		}
+		if hit.IsPartial {
+			states[hit.Line] = sourceLinePartial
+		} else if hit.Hits > 0 {
+			states[hit.Line] = sourceLineCovered
+		} else {
+			states[hit.Line] = sourceLineUncovered
+		}
+	}
+	return states
+}
+
+// getVisibleSourceLines returns the line numbers to show, every line when context is negative,
+// otherwise only uncovered or partly covered lines and the context lines around them. Nil means there is nothing to show.
+func getVisibleSourceLines(states []int, context int) map[int]bool {
+	visible := make(map[int]bool)
+	for line := 1; line < len(states); line++ {
+		if context < 0 {
+			visible[line] = true
+		} else if states[line] == sourceLineUncovered || states[line] == sourceLinePartial {
+			for near := line - context; near <= line+context; near++ {
+				if near >= 1 && near < len(states) {
+					visible[near] = true
+				}
+			}
+		}
+	}
+	if len(visible) == 0 {
+		return nil
+	}
+	return visible
+}
+
+func writeSourceLine(writer *bufio.Writer, lineNum int, width int, state int, line string, useColor bool) {
+	marker := sourceMarkers[state]
+	if !useColor {
+		fmt.Fprintf(writer, "%*d %s │ %s\n", width, lineNum, marker, line)
+		return
+	}
+
+	switch state {
+	case sourceLineCovered:
+		marker = ansiGreen + marker + ansiReset
+	case sourceLineUncovered:
+		marker = ansiRed + marker + ansiReset
+		line = ansiRed + line + ansiReset
+	case sourceLinePartial:
+		marker = ansiYellow + marker + ansiReset
+		line = ansiYellow + line + ansiReset
+	}
+	fmt.Fprintf(writer, "%s%*d%s %s │ %s\n", ansiGrey, width, lineNum, ansiReset, marker, line)
+}
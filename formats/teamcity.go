@@ -0,0 +1,44 @@
+package formats
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+// FormatTeamcity writes the statement and block coverage as TeamCity build statistic service messages.
+func FormatTeamcity(context *lib.ReportContext) error {
+	out, _, err := getTextOutput(context)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	blocks := context.GetProfileBlocks()
+	statements, coveredStatements := lib.GetStatementCounts(blocks)
+	writeTeamcityStatistic(out, "CodeCoverageS", getTeamcityPct(coveredStatements, statements))
+	writeTeamcityStatistic(out, "CodeCoverageAbsSCovered", fmt.Sprint(coveredStatements))
+	writeTeamcityStatistic(out, "CodeCoverageAbsSTotal", fmt.Sprint(statements))
+
+	blockCount, coveredBlocks := lib.GetBlockCounts(blocks)
+	writeTeamcityStatistic(out, "CodeCoverageB", getTeamcityPct(coveredBlocks, blockCount))
+	writeTeamcityStatistic(out, "CodeCoverageAbsBCovered", fmt.Sprint(coveredBlocks))
+	writeTeamcityStatistic(out, "CodeCoverageAbsBTotal", fmt.Sprint(blockCount))
+
+	return nil
+}
+
+func writeTeamcityStatistic(out io.Writer, key string, value string) {
+	fmt.Fprintf(out, "##teamcity[buildStatisticValue key='%s' value='%s']\n", key, value)
+}
+
+func getTeamcityPct(covered, total int) string {
+	if total == 0 {
+		return "0.00"
+	}
+	return fmt.Sprintf("%.2f", 100*float64(covered)/float64(total))
+}
@@ -0,0 +1,98 @@
+package formats
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/giocirque/gocovrpt/lib"
+	"golang.org/x/tools/cover"
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiGrey   = "\033[90m"
+)
+
+type textWriter struct {
+	writer   io.Writer
+	maxDepth int
+	useColor bool
+}
+
+func FormatText(context *lib.ReportContext) error {
+	out, useColor, err := getTextOutput(context)
+	if err != nil {
+		return err
+	}
+	if out != os.Stdout {
+		defer out.Close()
+	}
+
+	tabber := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	tw := textWriter{writer: tabber, maxDepth: context.Config.Depth, useColor: useColor}
+
+	rootFolder := context.GetPseudoFolder()
+	tw.writeRow(context.Config.ProjectName, "", context.GetProfileBlocks(), context.CoveredPct)
+	tw.writeChildren(rootFolder.ReportedFolders, context.GetRootFiles(), "", 1)
+
+	return tabber.Flush()
+}
+
+// getTextOutput returns the file to write text to, stdout unless an output was given, and whether to color it.
+func getTextOutput(context *lib.ReportContext) (*os.File, bool, error) {
+	if context.Config.Output == lib.StdPath {
+		return os.Stdout, lib.IsColorTerminal(os.Stdout), nil
+	}
+
+	file, err := lib.MakeFile(context.Output)
+	return file, false, err
+}
+
+func (tw *textWriter) writeChildren(folders []*lib.ReportedFolder, files []*lib.ReportedFile, prefix string, depth int) {
+	if tw.maxDepth > 0 && depth > tw.maxDepth {
+		return
+	}
+
+	count := len(folders) + len(files)
+	for i, folder := range folders {
+		branch, indent := getTreeBranch(i == count-1)
+		tw.writeRow(folder.FolderName+"/", prefix+branch, folder.GetProfileBlocks(), folder.CoveredPct)
+		tw.writeChildren(folder.ReportedFolders, folder.ReportedFiles, prefix+indent, depth+1)
+	}
+	for i, file := range files {
+		branch, _ := getTreeBranch(len(folders)+i == count-1)
+		tw.writeRow(file.FileName, prefix+branch, file.Profile.Blocks, file.CoveredPct)
+	}
+}
+
+func (tw *textWriter) writeRow(name string, prefix string, blocks []cover.ProfileBlock, coveredPct float64) {
+	statements, coveredStatements := lib.GetStatementCounts(blocks)
+	pct := fmt.Sprintf("%.2f%%", coveredPct)
+	if tw.useColor {
+		pct = getCoverageAnsiColor(coveredPct) + pct + ansiReset
+		prefix = ansiGrey + prefix + ansiReset
+	}
+	fmt.Fprintf(tw.writer, "%s%s\t%s\t%d/%d\n", prefix, name, pct, coveredStatements, statements)
+}
+
+func getTreeBranch(isLast bool) (branch, indent string) {
+	if isLast {
+		return "└── ", "    "
+	}
+	return "├── ", "│   "
+}
+
+func getCoverageAnsiColor(percent float64) string {
+	color := ansiRed
+	if percent >= 80 {
+		color = ansiGreen
+	} else if percent >= 50 {
+		color = ansiYellow
+	}
+	return color
+}
@@ -0,0 +1,26 @@
+package formats
+
+import (
+	"encoding/xml"
+
+	"github.com/giocirque/gocovrpt/lib"
+)
+
+func FormatXml(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	file.WriteString(xml.Header)
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(NewReportDocument(context))
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteString("\n")
+	return err
+}
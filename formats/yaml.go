@@ -0,0 +1,23 @@
+package formats
+
+import (
+	"github.com/giocirque/gocovrpt/lib"
+	"gopkg.in/yaml.v3"
+)
+
+func FormatYaml(context *lib.ReportContext) error {
+	file, err := lib.MakeFile(context.Output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := yaml.NewEncoder(file)
+	encoder.SetIndent(2)
+	err = encoder.Encode(NewReportDocument(context))
+	if err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
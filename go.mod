@@ -3,8 +3,10 @@ module github.com/giocirque/gocovrpt
 go 1.20
 
 require (
+	github.com/klauspost/compress v1.17.9
 	github.com/spf13/cobra v1.7.0
 	golang.org/x/tools v0.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
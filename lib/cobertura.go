@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+
+	"golang.org/x/tools/cover"
+)
+
+// coberturaInput is the part of a Cobertura XML report that's read as an input
+type coberturaInput struct {
+	XMLName  xml.Name `xml:"coverage"`
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			FileName string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int     `xml:"number,attr"`
+				Hits   float64 `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// ParseCobertura parses the class lines of a Cobertura XML report into profiles, summing the hits of files split across classes.
+func ParseCobertura(content []byte) ([]*cover.Profile, error) {
+	var model coberturaInput
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	// The DTD isn't needed to read the report.
+	decoder.Strict = false
+	if err := decoder.Decode(&model); err != nil {
+		return nil, fmt.Errorf("reading Cobertura XML: %w", err)
+	}
+
+	fileLines := make(map[string]map[int]int)
+	for _, pkg := range model.Packages {
+		for _, class := range pkg.Classes {
+			fileName := resolveCoberturaFile(model.Sources, class.FileName)
+			lines, exists := fileLines[fileName]
+			if !exists {
+				lines = make(map[int]int)
+				fileLines[fileName] = lines
+			}
+			for _, line := range class.Lines {
+				lines[line.Number] += int(line.Hits)
+			}
+		}
+	}
+	return newLineProfiles(ModeAny, fileLines), nil
+}
+
+// resolveCoberturaFile joins the class file name to the first source it exists in, or keeps it as is.
+func resolveCoberturaFile(sources []string, fileName string) string {
+	if path.IsAbs(fileName) {
+		return fileName
+	}
+	for _, source := range sources {
+		if sourcePath := path.Join(source, fileName); FileExists(sourcePath) {
+			return sourcePath
+		}
+	}
+	return fileName
+}
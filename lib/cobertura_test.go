@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestParseCobertura(t *testing.T) {
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sourceFile := filepath.ToSlash(filepath.Join(sourceDir, "a.go"))
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []*cover.Profile
+		wantErr  bool
+	}{
+		{
+			name: "classes",
+			content: `<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.5">
+  <sources><source>/missing</source><source>` + filepath.ToSlash(sourceDir) + `</source></sources>
+  <packages><package name="a"><classes>
+    <class name="A" filename="a.go"><lines><line number="2" hits="3"/><line number="1" hits="0"/></lines></class>
+    <class name="B" filename="b.go"><lines><line number="4" hits="1"/></lines></class>
+  </classes></package></packages>
+</coverage>`,
+			expected: []*cover.Profile{
+				newLineProfile(sourceFile, lineBlock(1, 0), lineBlock(2, 3)),
+				newLineProfile("b.go", lineBlock(4, 1)),
+			},
+		},
+		{
+			name: "file split across classes",
+			content: `<coverage><packages><package><classes>
+    <class name="A" filename="/src/a.go"><lines><line number="1" hits="1"/></lines></class>
+    <class name="A$1" filename="/src/a.go"><lines><line number="1" hits="2"/><line number="5" hits="0"/></lines></class>
+  </classes></package></packages></coverage>`,
+			expected: []*cover.Profile{newLineProfile("/src/a.go", lineBlock(1, 3), lineBlock(5, 0))},
+		},
+		{name: "not cobertura", content: `<report></report>`, wantErr: true},
+		{name: "malformed", content: `<coverage><packages>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseCobertura([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCobertura() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ParseCobertura() = %v, want %v", describeProfiles(actual), describeProfiles(tt.expected))
+			}
+		})
+	}
+}
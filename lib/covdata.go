@@ -0,0 +1,387 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// The binary coverage data layout written by `go build -cover` binaries, see internal/coverage in the Go source.
+const (
+	covMetaFilePrefix    = "covmeta."
+	covCounterFilePrefix = "covcounters."
+	covMetaHeaderSize    = 56
+	covPkgHeaderSize     = 44
+	covCounterHeaderSize = 32
+	covCounterFooterSize = 16
+	covSegmentHeaderSize = 16
+	covCounterRaw        = 1
+	covCounterUleb128    = 2
+)
+
+var (
+	covMetaMagic    = []byte{0x00, 'c', 'v', 'm'}
+	covCounterMagic = []byte{0x00, 'c', 'w', 'm'}
+	covModes        = []string{"", "set", "count", "atomic"}
+)
+
+// covUnit is a coverable unit of a function, which becomes a cover.ProfileBlock
+type covUnit struct {
+	fileName  string
+	startLine int
+	startCol  int
+	endLine   int
+	endCol    int
+	numStmt   int
+}
+
+// covMetaFile is the decoded content of a covmeta file
+type covMetaFile struct {
+	mode string
+	// The coverable units of each function, indexed by package and then function
+	units [][][]covUnit
+}
+
+// IsCoverDir returns true if dirPath is a GOCOVERDIR directory with at least one covmeta file.
+func IsCoverDir(dirPath string) bool {
+	matches, err := filepath.Glob(filepath.Join(dirPath, covMetaFilePrefix+"*"))
+	return err == nil && len(matches) > 0
+}
+
+// ReadCoverDir reads the covmeta and covcounters files of a GOCOVERDIR directory into profiles, as `go tool covdata textfmt` would.
+func ReadCoverDir(dirPath string) ([]*cover.Profile, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && IsCoverDataFile(name) {
+			files[name], err = os.ReadFile(filepath.Join(dirPath, name))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ReadCoverData(files)
+}
+
+// IsCoverDataFile returns true if the file name is that of a covmeta or covcounters file.
+func IsCoverDataFile(fileName string) bool {
+	return strings.HasPrefix(fileName, covMetaFilePrefix) || strings.HasPrefix(fileName, covCounterFilePrefix)
+}
+
+// ReadCoverData reads the content of the covmeta and covcounters files of a GOCOVERDIR, keyed by file name, into profiles.
+func ReadCoverData(files map[string][]byte) ([]*cover.Profile, error) {
+	metaFiles := make(map[string]*covMetaFile)
+	counterFiles := make([]string, 0)
+	mode := ""
+	for name, data := range files {
+		if strings.HasPrefix(name, covMetaFilePrefix) {
+			hash, meta, err := decodeCovMetaFile(data)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", name, err)
+			}
+			if mode != "" && mode != meta.mode {
+				return nil, fmt.Errorf("reading %s: mixed coverage modes %s and %s", name, mode, meta.mode)
+			}
+			mode = meta.mode
+			metaFiles[hash] = meta
+		} else if strings.HasPrefix(name, covCounterFilePrefix) {
+			counterFiles = append(counterFiles, name)
+		}
+	}
+
+	// Every unit of every meta file is reported, with the counts of all the runs merged together.
+	counts := make(map[covUnit]int)
+	for _, meta := range metaFiles {
+		for _, pkg := range meta.units {
+			for _, fn := range pkg {
+				for _, unit := range fn {
+					counts[unit] += 0
+				}
+			}
+		}
+	}
+	for _, name := range counterFiles {
+		err := decodeCovCounterFile(files[name], metaFiles, counts)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+	}
+
+	return newCovProfiles(mode, counts), nil
+}
+
+func newCovProfiles(mode string, counts map[covUnit]int) []*cover.Profile {
+	profileMap := make(map[string]*cover.Profile)
+	for unit, count := range counts {
+		if mode == "set" && count > 1 {
+			count = 1
+		}
+		profile, exists := profileMap[unit.fileName]
+		if !exists {
+			profile = &cover.Profile{FileName: unit.fileName, Mode: mode}
+			profileMap[unit.fileName] = profile
+		}
+		profile.Blocks = append(profile.Blocks, cover.ProfileBlock{
+			StartLine: unit.startLine,
+			StartCol:  unit.startCol,
+			EndLine:   unit.endLine,
+			EndCol:    unit.endCol,
+			NumStmt:   unit.numStmt,
+			Count:     count,
+		})
+	}
+
+	profiles := make([]*cover.Profile, 0, len(profileMap))
+	for _, profile := range profileMap {
+		SortProfileBlocks(profile.Blocks)
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].FileName < profiles[j].FileName
+	})
+	return profiles
+}
+
+// decodeCovMetaFile decodes a covmeta file, returning its hash and the units of each function.
+func decodeCovMetaFile(data []byte) (string, *covMetaFile, error) {
+	if len(data) < covMetaHeaderSize || !bytes.Equal(data[:4], covMetaMagic) {
+		return "", nil, fmt.Errorf("not a coverage meta-data file")
+	}
+	le := binary.LittleEndian
+	entries := le.Uint64(data[16:24])
+	hash := fmt.Sprintf("%x", data[24:40])
+	strTabOffset := le.Uint32(data[40:44])
+	strTabLength := le.Uint32(data[44:48])
+	modeIdx := int(data[48])
+	if modeIdx < 1 || modeIdx >= len(covModes) {
+		return "", nil, fmt.Errorf("unsupported counter mode %d", modeIdx)
+	}
+
+	// Each package has an offset and a length in the header, which bounds the count before it's allocated.
+	if entries > uint64(len(data)-covMetaHeaderSize)/16 {
+		return "", nil, fmt.Errorf("malformed meta-data file header with %d packages", entries)
+	}
+
+	reader := covReader{data: data, off: covMetaHeaderSize}
+	offsets := make([]uint64, entries)
+	for i := range offsets {
+		offsets[i] = reader.uint64()
+	}
+	lengths := make([]uint64, entries)
+	for i := range lengths {
+		lengths[i] = reader.uint64()
+	}
+	strTab := covReader{data: reader.slice(int(strTabOffset), int(strTabLength))}
+	if reader.err != nil || strTab.readStrings() == nil {
+		return "", nil, fmt.Errorf("truncated meta-data file header")
+	}
+
+	meta := &covMetaFile{mode: covModes[modeIdx], units: make([][][]covUnit, entries)}
+	for i := range offsets {
+		payload := reader.slice(int(offsets[i]), int(lengths[i]))
+		if reader.err != nil {
+			return "", nil, fmt.Errorf("truncated package %d", i)
+		}
+		units, err := decodeCovPackage(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("package %d: %w", i, err)
+		}
+		meta.units[i] = units
+	}
+	return hash, meta, nil
+}
+
+// decodeCovPackage decodes a package payload of a covmeta file into the units of each function.
+func decodeCovPackage(payload []byte) ([][]covUnit, error) {
+	if len(payload) < covPkgHeaderSize {
+		return nil, fmt.Errorf("truncated package header")
+	}
+	numFuncs := binary.LittleEndian.Uint32(payload[40:44])
+
+	if uint64(numFuncs) > uint64(len(payload)-covPkgHeaderSize)/4 {
+		return nil, fmt.Errorf("malformed package header with %d functions", numFuncs)
+	}
+
+	reader := covReader{data: payload, off: covPkgHeaderSize}
+	funcOffsets := make([]uint32, numFuncs)
+	for i := range funcOffsets {
+		funcOffsets[i] = reader.uint32()
+	}
+	strings := reader.readStrings()
+	if reader.err != nil {
+		return nil, fmt.Errorf("truncated package string table")
+	}
+
+	funcs := make([][]covUnit, numFuncs)
+	for i, funcOffset := range funcOffsets {
+		reader.off = int(funcOffset)
+		numUnits := reader.uleb128()
+		reader.uleb128() // The function name
+		fileIdx := reader.uleb128()
+		// Each unit is at least five ULEB128 numbers of a byte.
+		if reader.err != nil || fileIdx >= uint64(len(strings)) || numUnits > uint64(len(payload)-reader.off)/5 {
+			return nil, fmt.Errorf("malformed function %d", i)
+		}
+		units := make([]covUnit, 0, numUnits)
+		for u := uint64(0); u < numUnits && reader.err == nil; u++ {
+			units = append(units, covUnit{
+				fileName:  strings[fileIdx],
+				startLine: int(reader.uleb128()),
+				startCol:  int(reader.uleb128()),
+				endLine:   int(reader.uleb128()),
+				endCol:    int(reader.uleb128()),
+				numStmt:   int(reader.uleb128()),
+			})
+		}
+		if reader.err != nil {
+			return nil, fmt.Errorf("malformed function %d", i)
+		}
+		funcs[i] = units
+	}
+	return funcs, nil
+}
+
+// decodeCovCounterFile adds the counters of a covcounters file to the counts of its meta file's units.
+func decodeCovCounterFile(data []byte, metaFiles map[string]*covMetaFile, counts map[covUnit]int) error {
+	if len(data) < covCounterHeaderSize+covCounterFooterSize || !bytes.Equal(data[:4], covCounterMagic) {
+		return fmt.Errorf("not a coverage counter data file")
+	}
+	meta, exists := metaFiles[fmt.Sprintf("%x", data[8:24])]
+	if !exists {
+		return fmt.Errorf("no meta-data file for the counters")
+	}
+	flavor := data[24]
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[25] != 0 {
+		order = binary.BigEndian
+	}
+	footer := data[len(data)-covCounterFooterSize:]
+	numSegments := binary.LittleEndian.Uint32(footer[8:12])
+
+	reader := covReader{data: data, off: covCounterHeaderSize}
+	readCounter := func() uint64 {
+		if flavor == covCounterUleb128 {
+			return reader.uleb128()
+		}
+		if value := reader.next(4); value != nil {
+			return uint64(order.Uint32(value))
+		}
+		return 0
+	}
+	if flavor != covCounterRaw && flavor != covCounterUleb128 {
+		return fmt.Errorf("unknown counter flavor %d", flavor)
+	}
+
+	for segment := uint32(0); segment < numSegments; segment++ {
+		header := reader.slice(reader.off, covSegmentHeaderSize)
+		if reader.err != nil {
+			return fmt.Errorf("truncated segment %d", segment)
+		}
+		funcEntries := binary.LittleEndian.Uint64(header[0:8])
+		// Skip the string table and arguments, which only describe the run. The arguments length includes the padding
+		// that aligns the counters to 4 bytes from the start of the segment.
+		skip := int(binary.LittleEndian.Uint32(header[8:12])) + int(binary.LittleEndian.Uint32(header[12:16]))
+		reader.off += covSegmentHeaderSize + skip
+
+		for f := uint64(0); f < funcEntries; f++ {
+			if reader.off < len(data) && bytes.HasPrefix(data[reader.off:], covCounterMagic) {
+				// The encoder doesn't reset the function count between segments, so later segments overstate it and end
+				// at their footer instead. No function entry starts with the magic, as they have at least one counter.
+				break
+			}
+			numCounters := readCounter()
+			pkgIdx := readCounter()
+			funcIdx := readCounter()
+			if reader.err != nil || pkgIdx >= uint64(len(meta.units)) || funcIdx >= uint64(len(meta.units[pkgIdx])) {
+				return fmt.Errorf("malformed function entry in segment %d", segment)
+			}
+			units := meta.units[pkgIdx][funcIdx]
+			for c := uint64(0); c < numCounters && reader.err == nil; c++ {
+				count := readCounter()
+				if c < uint64(len(units)) {
+					counts[units[c]] += int(count)
+				}
+			}
+			if reader.err != nil {
+				return fmt.Errorf("truncated counters in segment %d", segment)
+			}
+		}
+		reader.off += covCounterFooterSize
+	}
+	return nil
+}
+
+// covReader reads little endian values and ULEB128 numbers from a byte slice, remembering the first overrun.
+type covReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+// slice returns the bytes at the offset, or nil once anything has been read past the end of the data.
+func (cr *covReader) slice(off int, length int) []byte {
+	if cr.err != nil || off < 0 || length < 0 || off > len(cr.data) || length > len(cr.data)-off {
+		cr.err = fmt.Errorf("read past the end of the data")
+		return nil
+	}
+	return cr.data[off : off+length]
+}
+
+// next returns the bytes at the current offset and moves past them, or nil as slice does.
+func (cr *covReader) next(length int) []byte {
+	value := cr.slice(cr.off, length)
+	cr.off += length
+	return value
+}
+
+func (cr *covReader) uint32() uint32 {
+	if value := cr.next(4); value != nil {
+		return binary.LittleEndian.Uint32(value)
+	}
+	return 0
+}
+
+func (cr *covReader) uint64() uint64 {
+	if value := cr.next(8); value != nil {
+		return binary.LittleEndian.Uint64(value)
+	}
+	return 0
+}
+
+func (cr *covReader) uleb128() (value uint64) {
+	for shift := uint(0); shift < 64; shift += 7 {
+		b := cr.next(1)
+		if b == nil {
+			break
+		}
+		value |= uint64(b[0]&0x7F) << shift
+		if b[0]&0x80 == 0 {
+			break
+		}
+	}
+	return
+}
+
+// readStrings reads a string table, a ULEB128 count followed by ULEB128 length prefixed strings.
+func (cr *covReader) readStrings() []string {
+	count := cr.uleb128()
+	result := make([]string, 0)
+	for i := uint64(0); i < count && cr.err == nil; i++ {
+		length := int(cr.uleb128())
+		result = append(result, string(cr.next(length)))
+	}
+	if cr.err != nil {
+		return nil
+	}
+	return result
+}
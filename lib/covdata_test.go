@@ -0,0 +1,123 @@
+package lib
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+// The fixtures in testdata/covdata are the covmeta and covcounters files of a `go build -cover -covermode=atomic` binary run
+// twice, and the expected profiles from `go tool covdata textfmt`. The raw and segments counter files were re-encoded from
+// the runtime's output with internal/coverage/encodecounter, as the raw flavor and as one file with a segment for each run.
+func TestReadCoverDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		expected string
+	}{
+		// The runtime's output, one ULEB128 counter file for each run
+		{name: "uleb128", dir: "uleb128", expected: "uleb128.txt"},
+		// The first run as a raw counter file
+		{name: "raw", dir: "raw", expected: "raw.txt"},
+		// Both runs as segments of one ULEB128 counter file. The second segment doesn't start on a 4 byte boundary, and its
+		// header overstates its function count. The expected profile is the sum of the runs, as `go tool covdata textfmt`
+		// only reads the first segment of a file.
+		{name: "segments", dir: "segments", expected: "uleb128.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := cover.ParseProfiles(filepath.Join("testdata", "covdata", tt.expected))
+			if err != nil {
+				t.Fatalf("reading the expected profiles: %v", err)
+			}
+
+			actual, err := ReadCoverDir(filepath.Join("testdata", "covdata", tt.dir))
+			if err != nil {
+				t.Fatalf("ReadCoverDir() error = %v", err)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("ReadCoverDir() = %v, want %v", describeProfiles(actual), describeProfiles(expected))
+			}
+		})
+	}
+}
+
+// Malformed files must fail with an error rather than panic or allocate what their headers claim.
+func TestReadCoverDataMalformed(t *testing.T) {
+	le := binary.LittleEndian
+	tests := []struct {
+		name   string
+		mutate func(meta []byte, counters []byte) ([]byte, []byte)
+	}{
+		{name: "meta package count", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			le.PutUint64(meta[16:24], 0xFFFFFFFFFFFF)
+			return meta, counters
+		}},
+		{name: "meta string table length", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			le.PutUint32(meta[44:48], 0xFFFFFFFF)
+			return meta, counters
+		}},
+		{name: "meta package offset", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			le.PutUint64(meta[56:64], 0xFFFFFFFFFFFFFFF0)
+			return meta, counters
+		}},
+		{name: "meta truncated", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			return meta[:len(meta)/2], counters
+		}},
+		{name: "package function count", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			pkgOffset := le.Uint64(meta[56:64])
+			le.PutUint32(meta[pkgOffset+40:pkgOffset+44], 0xFFFFFFFF)
+			return meta, counters
+		}},
+		{name: "counters segment count", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			le.PutUint32(counters[len(counters)-8:len(counters)-4], 0xFFFFFFFF)
+			return meta, counters
+		}},
+		{name: "counters truncated", mutate: func(meta []byte, counters []byte) ([]byte, []byte) {
+			return meta, append(counters[:80:80], counters[len(counters)-16:]...)
+		}},
+	}
+
+	dir := filepath.Join("testdata", "covdata", "raw")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var metaName, countersName string
+			var meta, counters []byte
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if strings.HasPrefix(entry.Name(), covMetaFilePrefix) {
+					metaName, meta = entry.Name(), data
+				} else {
+					countersName, counters = entry.Name(), data
+				}
+			}
+
+			meta, counters = tt.mutate(meta, counters)
+			profiles, err := ReadCoverData(map[string][]byte{metaName: meta, countersName: counters})
+			if err == nil {
+				t.Errorf("ReadCoverData() = %v, want an error", describeProfiles(profiles))
+			}
+		})
+	}
+}
+
+func describeProfiles(profiles []*cover.Profile) []cover.Profile {
+	result := make([]cover.Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		result = append(result, *profile)
+	}
+	return result
+}
@@ -0,0 +1,266 @@
+package lib
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// The number of bytes read from a file to tell whether it's a coverage profile while scanning a directory
+const sniffSize = 512
+
+// The extensions of compressed files and archives that are read while scanning a directory
+var packedExts = []string{".gz", ".tgz", ".zst", ".zip", ".tar"}
+
+// A FoundInput is an input found by FindInputs
+type FoundInput struct {
+	// The path of the input, or "-" for stdin
+	Path string
+	// Whether the input was found by scanning a directory, so it only looks like coverage and is skipped if it can't be read
+	IsScanned bool
+}
+
+// FindInputs expands the glob patterns and directories of the inputs into the profile files and GOCOVERDIR directories they
+// contain, skipping anything that matches an exclude pattern or is the report's own output, and logs what was found. Plain
+// files and stdin are kept as is.
+func FindInputs(inputs []string, excludes []string, output string, logger io.Writer) ([]FoundInput, error) {
+	for _, pattern := range append(append([]string{}, inputs...), excludes...) {
+		if err := validateGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	finder := inputFinder{excludes: excludes, logger: logger, seen: make(map[string]bool)}
+	if output != StdPath {
+		finder.output, _ = filepath.Abs(output)
+	}
+	for _, input := range inputs {
+		var err error
+		info, statErr := os.Stat(input)
+		switch {
+		case input == StdPath:
+			finder.add(input, false)
+		case isGlob(input):
+			err = finder.findGlob(input)
+		case statErr == nil && info.IsDir() && !IsCoverDir(input):
+			err = finder.findDir(input)
+		case !finder.isExcluded(input):
+			// Missing files are kept, so reading them reports the error.
+			finder.add(input, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return finder.found, nil
+}
+
+// MatchGlob returns true if the slash separated name matches the pattern, where a `**` element matches any number of
+// directories and the other elements are matched as by path.Match.
+func MatchGlob(pattern string, name string) bool {
+	return matchGlobParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobParts(pattern []string, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchGlobParts(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func isGlob(input string) bool {
+	return strings.ContainsAny(input, "*?[")
+}
+
+func validateGlob(pattern string) error {
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if _, err := path.Match(part, ""); err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// inputFinder collects the inputs found by FindInputs, once each and in the order they're found
+type inputFinder struct {
+	excludes []string
+	// The absolute path of the report's output, a file or a directory, which is never an input
+	output string
+	logger io.Writer
+	seen   map[string]bool
+	found  []FoundInput
+}
+
+func (inf *inputFinder) add(input string, isScanned bool) {
+	if !inf.seen[filepath.Clean(input)] {
+		inf.seen[filepath.Clean(input)] = true
+		inf.found = append(inf.found, FoundInput{Path: input, IsScanned: isScanned})
+	}
+}
+
+// isExcluded returns true if the path is the report's output, or is inside it, or matches an exclude pattern. Patterns
+// without a slash match the base name at any depth.
+func (inf *inputFinder) isExcluded(filePath string) bool {
+	if absPath, err := filepath.Abs(filePath); err == nil && inf.output != "" {
+		if absPath == inf.output || strings.HasPrefix(absPath, inf.output+string(os.PathSeparator)) {
+			return true
+		}
+	}
+
+	slashed := filepath.ToSlash(filepath.Clean(filePath))
+	for _, exclude := range inf.excludes {
+		pattern := filepath.ToSlash(filepath.Clean(exclude))
+		if MatchGlob(pattern, slashed) || (!strings.Contains(pattern, "/") && MatchGlob(pattern, path.Base(slashed))) {
+			return true
+		}
+	}
+	return false
+}
+
+// findGlob walks from the fixed leading directories of the pattern, adding the files and GOCOVERDIR directories that match.
+func (inf *inputFinder) findGlob(input string) error {
+	pattern := filepath.ToSlash(filepath.Clean(input))
+	parts := strings.Split(pattern, "/")
+	root := ""
+	for len(parts) > 1 && !isGlob(parts[0]) {
+		root += parts[0] + "/"
+		parts = parts[1:]
+	}
+	if root == "" {
+		root = "."
+	}
+
+	count := len(inf.found)
+	err := filepath.WalkDir(filepath.FromSlash(root), func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		slashed := filepath.ToSlash(filePath)
+		if inf.isExcluded(filePath) {
+			return inf.skip(entry)
+		} else if entry.IsDir() && IsCoverDir(filePath) {
+			if MatchGlob(pattern, slashed) || MatchGlob(pattern, path.Join(slashed, covMetaFilePrefix+"*")) {
+				inf.add(filePath, false)
+			}
+			return filepath.SkipDir
+		} else if !entry.IsDir() && MatchGlob(pattern, slashed) {
+			inf.add(filePath, false)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	inf.logFound(input, count)
+	return err
+}
+
+// findDir walks the directory, adding the coverage profiles, archives of them, and GOCOVERDIR directories inside it.
+func (inf *inputFinder) findDir(dirPath string) error {
+	count := len(inf.found)
+	err := filepath.WalkDir(dirPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if inf.isExcluded(filePath) {
+			return inf.skip(entry)
+		} else if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		} else if entry.IsDir() && IsCoverDir(filePath) {
+			inf.add(filePath, true)
+			return filepath.SkipDir
+		} else if !entry.IsDir() && entry.Type().IsRegular() && isProfileFile(filePath) {
+			inf.add(filePath, true)
+		}
+		return nil
+	})
+	inf.logFound(dirPath, count)
+	return err
+}
+
+func (inf *inputFinder) skip(entry fs.DirEntry) error {
+	if entry.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+func (inf *inputFinder) logFound(input string, count int) {
+	found := inf.found[count:]
+	noun := "inputs"
+	if len(found) == 1 {
+		noun = "input"
+	}
+	fmt.Fprintf(inf.logger, "Found %d %s for %s\n", len(found), noun, input)
+	for _, foundInput := range found {
+		fmt.Fprintf(inf.logger, "  %s\n", foundInput.Path)
+	}
+}
+
+// isProfileFile returns true if the start of the file, decompressed if needed, looks like a coverage profile, or the file is
+// an archive. Compressed files and archives are only considered when their extension says so.
+func isProfileFile(filePath string) bool {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	isPacked := false
+	for _, ext := range packedExts {
+		isPacked = isPacked || strings.HasSuffix(filePath, ext)
+	}
+	return isProfileStream(file, isPacked)
+}
+
+// isProfileStream sniffs the start of the stream, decompressing it first when it's packed and compressed.
+func isProfileStream(reader io.Reader, isPacked bool) bool {
+	head := make([]byte, sniffSize)
+	length, _ := io.ReadFull(reader, head)
+	head = head[:length]
+	if !isPacked {
+		return isProfileText(head)
+	}
+
+	stream := io.MultiReader(bytes.NewReader(head), reader)
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		decompressed, err := gzip.NewReader(stream)
+		if err != nil {
+			return false
+		}
+		defer decompressed.Close()
+		return isProfileStream(decompressed, true)
+	case bytes.HasPrefix(head, zstdMagic):
+		decompressed, err := zstd.NewReader(stream)
+		if err != nil {
+			return false
+		}
+		defer decompressed.Close()
+		return isProfileStream(decompressed, true)
+	}
+	// Archives are read whole, and entries that aren't coverage are ignored.
+	return isArchive(head) || isProfileText(head)
+}
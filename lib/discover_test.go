@@ -0,0 +1,127 @@
+package lib
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{pattern: "*.out", name: "a.out", expected: true},
+		{pattern: "*.out", name: "sub/a.out", expected: false},
+		{pattern: "**/*.out", name: "a.out", expected: true},
+		{pattern: "**/*.out", name: "sub/deep/a.out", expected: true},
+		{pattern: "sub/**", name: "sub/deep/a.out", expected: true},
+		{pattern: "sub/**/a.out", name: "sub/a.out", expected: true},
+		{pattern: "sub/**/a.out", name: "other/a.out", expected: false},
+		{pattern: "sub/?.out", name: "sub/a.out", expected: true},
+		{pattern: "sub/[bc].out", name: "sub/a.out", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if actual := MatchGlob(tt.pattern, tt.name); actual != tt.expected {
+				t.Errorf("MatchGlob() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindInputs(t *testing.T) {
+	root := t.TempDir()
+	profile := []byte("mode: set\na.go:1.1,2.2 1 1\n")
+	files := map[string][]byte{
+		"a.out":                profile,
+		"notes.txt":            []byte("hello\n"),
+		"notes.txt.gz":         gzipData(t, []byte("hello\n")),
+		"cov.out.gz":           gzipData(t, profile),
+		"sub/b.info":           []byte("TN:\nSF:/src/a.go\nDA:1,1\nend_of_record\n"),
+		"sub/cobertura.xml":    []byte(`<?xml version="1.0" ?><coverage line-rate="1"></coverage>`),
+		"sub/clover.xml":       []byte(`<?xml version="1.0" ?><coverage generated="1" clover="4.4.1"></coverage>`),
+		"vendor/c.out":         profile,
+		"report/coverage.json": profile,
+	}
+	for name, data := range files {
+		filePath := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scanned := func(name string) FoundInput {
+		return FoundInput{Path: filepath.Join(root, name), IsScanned: true}
+	}
+
+	tests := []struct {
+		name     string
+		inputs   []string
+		excludes []string
+		output   string
+		expected []FoundInput
+	}{
+		{
+			name:   "directory",
+			inputs: []string{root},
+			output: filepath.Join(root, "report"),
+			expected: []FoundInput{
+				scanned("a.out"), scanned("cov.out.gz"), scanned("sub/b.info"), scanned("sub/cobertura.xml"),
+				scanned("vendor/c.out"),
+			},
+		},
+		{
+			name:     "directory with excludes",
+			inputs:   []string{root},
+			excludes: []string{"vendor", "*.gz", filepath.Join(root, "sub/*.xml")},
+			output:   filepath.Join(root, "report/coverage.json"),
+			expected: []FoundInput{scanned("a.out"), scanned("sub/b.info")},
+		},
+		{
+			name:     "glob",
+			inputs:   []string{filepath.Join(root, "**/*.out")},
+			excludes: []string{"vendor"},
+			output:   StdPath,
+			expected: []FoundInput{{Path: filepath.Join(root, "a.out")}},
+		},
+		{
+			name:     "files and stdin",
+			inputs:   []string{StdPath, filepath.Join(root, "notes.txt"), filepath.Join(root, "missing.out"), StdPath},
+			output:   StdPath,
+			expected: []FoundInput{{Path: StdPath}, {Path: filepath.Join(root, "notes.txt")}, {Path: filepath.Join(root, "missing.out")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := FindInputs(tt.inputs, tt.excludes, tt.output, io.Discard)
+			if err != nil {
+				t.Fatalf("FindInputs() error = %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("FindInputs() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
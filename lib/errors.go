@@ -30,11 +30,37 @@ func UnresolvablePathError(fsPath string) AppError {
 	}
 }
 
+func NoInputsError(inputs []string) AppError {
+	return AppError{
+		Message: fmt.Sprintf("No coverage inputs found for %s", strings.Join(inputs, ", ")),
+		Code:    NoInputsCode,
+	}
+}
+
+func MixedModesError(inputs []InputProfiles) AppError {
+	return AppError{
+		Message: fmt.Sprintf("Coverage modes set and count can't be mixed: %s. Use --mode-coerce=set to report them all as set", describeInputModes(inputs)),
+		Code:    MixedModesCode,
+	}
+}
+
+func StdoutOutputError(format string) AppError {
+	return AppError{
+		Message: fmt.Sprintf("The %s format writes a directory and can't be written to stdout", format),
+		Code:    StdoutOutputCode,
+	}
+}
+
 const (
 	InvalidFormatCode = iota + 400
 	InvalidLevelCode
 	InvalidColorCode
 	UnresolvableFsPath
+	InvalidCsvKindCode
+	NoInputsCode
+	MixedModesCode
+	InvalidModeCoerceCode
+	StdoutOutputCode
 )
 
 func handleStopCode(err error) {
@@ -0,0 +1,131 @@
+package lib
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/cover"
+)
+
+// ReportedFunc is a function declaration of a ReportedFile and the coverage of the blocks inside it
+type ReportedFunc struct {
+	// The name of the function
+	Name string `json:"name" yaml:"name" xml:"name"`
+	// The receiver type of the function, empty for plain functions
+	Receiver string `json:"receiver" yaml:"receiver" xml:"receiver"`
+	// The start line number for this function
+	StartLine int `json:"start" yaml:"start" xml:"start"`
+	// The start column number for this function
+	StartCol int `json:"startCol" yaml:"startCol" xml:"startCol"`
+	// The stop line number for this function
+	StopLine int `json:"stop" yaml:"stop" xml:"stop"`
+	// The stop column number for this function
+	StopCol int `json:"stopCol" yaml:"stopCol" xml:"stopCol"`
+	// The number of statements in this function
+	Statements int `json:"statements" yaml:"statements" xml:"statements"`
+	// The number of statements executed in this function
+	CoveredStatements int `json:"coveredStatements" yaml:"coveredStatements" xml:"coveredStatements"`
+	// The number of times the first block of this function was executed
+	Hits int `json:"hits" yaml:"hits" xml:"hits"`
+}
+
+// IsExported returns true if the function name starts with an upper case letter.
+func (rf *ReportedFunc) IsExported() bool {
+	return token.IsExported(rf.Name)
+}
+
+// GetCoveredPct returns the percentage of statements covered in this function, optionally multiplied by 100.
+func (rf *ReportedFunc) GetCoveredPct(multiplied bool) (result float64) {
+	if rf.Statements == 0 {
+		return 0
+	}
+	result = float64(rf.CoveredStatements) / float64(rf.Statements)
+	if multiplied {
+		result *= 100
+	}
+	return
+}
+
+// GetFunctions parses the source code for this file and attributes the profile blocks to each function declaration.
+func (rf *ReportedFile) GetFunctions() ([]ReportedFunc, error) {
+	sourceCode, err := rf.GetSourceCode()
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	parsedFile, err := parser.ParseFile(fset, rf.SourceFile, sourceCode, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := make([]ReportedFunc, 0)
+	ast.Inspect(parsedFile, func(node ast.Node) bool {
+		decl, ok := node.(*ast.FuncDecl)
+		if !ok || decl.Body == nil {
+			// Only declarations with a body are instrumented, assembly stubs are not.
+			return true
+		}
+		start := fset.Position(decl.Pos())
+		end := fset.Position(decl.End())
+		fn := ReportedFunc{
+			Name:      decl.Name.Name,
+			Receiver:  getReceiverName(decl),
+			StartLine: start.Line,
+			StartCol:  start.Column,
+			StopLine:  end.Line,
+			StopCol:   end.Column,
+		}
+		fn.addBlocks(rf.Profile.Blocks)
+		funcs = append(funcs, fn)
+		return true
+	})
+	return funcs, nil
+}
+
+// addBlocks adds the statement counts of the blocks that fall inside the function.
+func (rf *ReportedFunc) addBlocks(blocks []cover.ProfileBlock) {
+	isFirst := true
+	for _, b := range blocks {
+		if b.StartLine > rf.StopLine || (b.StartLine == rf.StopLine && b.StartCol >= rf.StopCol) {
+			// Past the end of the function
+			continue
+		}
+		if b.EndLine < rf.StartLine || (b.EndLine == rf.StartLine && b.EndCol <= rf.StartCol) {
+			// Before the beginning of the function
+			continue
+		}
+		if isFirst {
+			rf.Hits = b.Count
+			isFirst = false
+		}
+		rf.Statements += b.NumStmt
+		if b.Count > 0 {
+			rf.CoveredStatements += b.NumStmt
+		}
+	}
+}
+
+func getReceiverName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := decl.Recv.List[0].Type
+	prefix := ""
+	if star, ok := expr.(*ast.StarExpr); ok {
+		prefix = "*"
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return prefix + ident.Name
+	}
+	return ""
+}
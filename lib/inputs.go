@@ -0,0 +1,257 @@
+package lib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/tools/cover"
+)
+
+// LineEndCol is the end column of the blocks made from line based inputs, which only know the line of each statement.
+const LineEndCol = math.MaxInt32
+
+// GetOutputEndCol returns the end column to write for a block, or 0 for the blocks of line based inputs, which end with
+// their line and leave the column out.
+func GetOutputEndCol(endCol int) int {
+	if endCol == LineEndCol {
+		return 0
+	}
+	return endCol
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic  = []byte{'P', 'K', 0x03, 0x04}
+	tarMagic  = []byte("ustar")
+)
+
+// The offset of the magic in a tar header
+const tarMagicOffset = 257
+
+// ReadProfiles reads the coverage profiles of an input, which is stdin when the input is "-", a GOCOVERDIR directory, or a file.
+// Files may be gzip or zstd compressed, or tar or zip archives, and are otherwise a Go coverage profile, LCOV tracefile,
+// or Cobertura XML file detected by its content.
+func ReadProfiles(input string) ([]*cover.Profile, error) {
+	if input == StdPath {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return parseProfiles(data)
+	}
+
+	info, err := os.Stat(input)
+	if err == nil && info.IsDir() && IsCoverDir(input) {
+		return ReadCoverDir(input)
+	}
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return nil, err
+	}
+	return parseProfiles(data)
+}
+
+// parseProfiles parses the content of an input file, unpacking it first if it's compressed or an archive.
+func parseProfiles(data []byte) ([]*cover.Profile, error) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return parseCompressedProfiles(reader)
+	case bytes.HasPrefix(data, zstdMagic):
+		reader, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return parseCompressedProfiles(reader)
+	case bytes.HasPrefix(data, zipMagic):
+		return parseZipProfiles(data)
+	case isTar(data):
+		return parseTarProfiles(data)
+	}
+
+	content := trimProfileContent(data)
+	switch {
+	case bytes.HasPrefix(content, []byte("mode:")):
+		return cover.ParseProfilesFromReader(bytes.NewReader(content))
+	case bytes.HasPrefix(content, []byte("<")):
+		return ParseCobertura(content)
+	case IsLcov(content):
+		return ParseLcov(content)
+	}
+	// Let the Go profile parser report what's wrong with the input.
+	return cover.ParseProfilesFromReader(bytes.NewReader(content))
+}
+
+// trimProfileContent trims the byte order mark and leading white space that would hide the format of a profile.
+func trimProfileContent(data []byte) []byte {
+	return bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\ufeff")), " \t\r\n")
+}
+
+func parseCompressedProfiles(reader io.Reader) ([]*cover.Profile, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseProfiles(data)
+}
+
+func parseZipProfiles(data []byte) ([]*cover.Profile, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string][]byte)
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		entries[file.Name], err = io.ReadAll(entry)
+		entry.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseArchiveProfiles(entries)
+}
+
+func parseTarProfiles(data []byte) ([]*cover.Profile, error) {
+	reader := tar.NewReader(bytes.NewReader(data))
+	entries := make(map[string][]byte)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		entries[header.Name], err = io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseArchiveProfiles(entries)
+}
+
+// parseArchiveProfiles parses the entries of an archive, reading the covmeta and covcounters files of each directory
+// together as a GOCOVERDIR, and any other entry that is a coverage profile on its own. Other entries are ignored.
+func parseArchiveProfiles(entries map[string][]byte) ([]*cover.Profile, error) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	profiles := make([]*cover.Profile, 0)
+	coverDirs := make(map[string]map[string][]byte)
+	for _, name := range names {
+		dir, fileName := path.Split(name)
+		if IsCoverDataFile(fileName) {
+			if _, exists := coverDirs[dir]; !exists {
+				coverDirs[dir] = make(map[string][]byte)
+			}
+			coverDirs[dir][fileName] = entries[name]
+			continue
+		}
+		if !isProfileData(entries[name]) {
+			continue
+		}
+		entryProfiles, err := parseProfiles(entries[name])
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		profiles = append(profiles, entryProfiles...)
+	}
+
+	dirs := make([]string, 0, len(coverDirs))
+	for dir := range coverDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		dirProfiles, err := ReadCoverData(coverDirs[dir])
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", dir, err)
+		}
+		profiles = append(profiles, dirProfiles...)
+	}
+	return profiles, nil
+}
+
+// isProfileData returns true if an archive entry is compressed, an archive, or a coverage profile in a known format.
+func isProfileData(data []byte) bool {
+	if bytes.HasPrefix(data, gzipMagic) || bytes.HasPrefix(data, zstdMagic) || isArchive(data) {
+		return true
+	}
+	return isProfileText(data)
+}
+
+// isProfileText returns true if the data starts like a Go coverage profile, LCOV tracefile, or Cobertura XML file. The
+// line-rate attribute, which Cobertura requires, tells its root element from Clover's.
+func isProfileText(data []byte) bool {
+	content := trimProfileContent(data)
+	if bytes.HasPrefix(content, []byte("<")) {
+		return bytes.Contains(content, []byte("<coverage")) && bytes.Contains(content, []byte("line-rate"))
+	}
+	return bytes.HasPrefix(content, []byte("mode:")) || IsLcov(content)
+}
+
+// isArchive returns true if the data starts with a zip or tar header.
+func isArchive(data []byte) bool {
+	return bytes.HasPrefix(data, zipMagic) || isTar(data)
+}
+
+func isTar(data []byte) bool {
+	return len(data) > tarMagicOffset+len(tarMagic) && bytes.Equal(data[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic)
+}
+
+// newLineProfiles makes profiles with a single statement block for each line of each file, as line based inputs have no columns.
+func newLineProfiles(mode string, fileLines map[string]map[int]int) []*cover.Profile {
+	profiles := make([]*cover.Profile, 0, len(fileLines))
+	for fileName, lines := range fileLines {
+		profile := &cover.Profile{
+			FileName: fileName,
+			Mode:     mode,
+			Blocks:   make([]cover.ProfileBlock, 0, len(lines)),
+		}
+		for line, hits := range lines {
+			profile.Blocks = append(profile.Blocks, cover.ProfileBlock{
+				StartLine: line,
+				StartCol:  1,
+				EndLine:   line,
+				EndCol:    LineEndCol,
+				NumStmt:   1,
+				Count:     hits,
+			})
+		}
+		SortProfileBlocks(profile.Blocks)
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].FileName < profiles[j].FileName
+	})
+	return profiles
+}
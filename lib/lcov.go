@@ -0,0 +1,67 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// IsLcov returns true if the content has the source file record of an LCOV tracefile.
+func IsLcov(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "SF:") {
+			return true
+		} else if line != "" && !strings.HasPrefix(line, "TN:") {
+			return false
+		}
+	}
+	return false
+}
+
+// ParseLcov parses the line records of an LCOV tracefile into profiles, summing the hits of files that appear more than once.
+func ParseLcov(content []byte) ([]*cover.Profile, error) {
+	fileLines := make(map[string]map[int]int)
+	var lines map[int]int
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		record := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(record, "SF:"):
+			fileName := strings.TrimPrefix(record, "SF:")
+			if _, exists := fileLines[fileName]; !exists {
+				fileLines[fileName] = make(map[int]int)
+			}
+			lines = fileLines[fileName]
+		case strings.HasPrefix(record, "DA:"):
+			if lines == nil {
+				return nil, fmt.Errorf("line %d: DA record outside of a source file", lineNum)
+			}
+			// DA:<line number>,<execution count>[,<checksum>]
+			fields := strings.Split(strings.TrimPrefix(record, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed DA record %q", lineNum, record)
+			}
+			line, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: malformed DA record %q", lineNum, record)
+			}
+			hits, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: malformed DA record %q", lineNum, record)
+			}
+			lines[line] += int(hits)
+		case record == "end_of_record":
+			lines = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newLineProfiles(ModeAny, fileLines), nil
+}
@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestIsLcov(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{name: "source file", content: "SF:/src/a.go\nDA:1,1\n", expected: true},
+		{name: "test name first", content: "\nTN:unit\nSF:/src/a.go\n", expected: true},
+		{name: "go profile", content: "mode: set\n", expected: false},
+		{name: "no source file", content: "TN:unit\n", expected: false},
+		{name: "empty", content: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsLcov([]byte(tt.content)); actual != tt.expected {
+				t.Errorf("IsLcov() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseLcov(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []*cover.Profile
+		wantErr  bool
+	}{
+		{
+			name:    "records",
+			content: "TN:unit\nSF:/src/b.go\nDA:3,0\nDA:1,2,checksum\nLF:2\nLH:1\nend_of_record\nSF:/src/a.go\nDA:7,1.0\nend_of_record\n",
+			expected: []*cover.Profile{
+				newLineProfile("/src/a.go", lineBlock(7, 1)),
+				newLineProfile("/src/b.go", lineBlock(1, 2), lineBlock(3, 0)),
+			},
+		},
+		{
+			name:     "repeated file",
+			content:  "SF:/src/a.go\nDA:1,2\nDA:2,0\nend_of_record\nSF:/src/a.go\nDA:1,3\nend_of_record\n",
+			expected: []*cover.Profile{newLineProfile("/src/a.go", lineBlock(1, 5), lineBlock(2, 0))},
+		},
+		{name: "line outside a file", content: "DA:1,2\n", wantErr: true},
+		{name: "missing count", content: "SF:/src/a.go\nDA:1\n", wantErr: true},
+		{name: "bad line number", content: "SF:/src/a.go\nDA:x,1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseLcov([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLcov() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ParseLcov() = %v, want %v", describeProfiles(actual), describeProfiles(tt.expected))
+			}
+		})
+	}
+}
+
+func newLineProfile(fileName string, blocks ...cover.ProfileBlock) *cover.Profile {
+	return &cover.Profile{FileName: fileName, Mode: ModeAny, Blocks: blocks}
+}
+
+func lineBlock(line int, count int) cover.ProfileBlock {
+	return cover.ProfileBlock{StartLine: line, StartCol: 1, EndLine: line, EndCol: LineEndCol, NumStmt: 1, Count: count}
+}
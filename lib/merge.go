@@ -0,0 +1,146 @@
+package lib
+
+import (
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// mergeSide tags a block with the profile it came from
+type mergeSide struct {
+	block   cover.ProfileBlock
+	isOther bool
+}
+
+// MergeProfileBlocks merges the blocks of two profiles of the same file, ORing the counts in set mode and summing them otherwise.
+// Blocks with identical boundaries are merged one to one. Where the boundaries overlap but differ, the finer side of the
+// overlap is kept and each of its blocks takes the highest count of the blocks it overlaps on the other side. The result
+// doesn't depend on which of the profiles comes first.
+func MergeProfileBlocks(mode string, blocks []cover.ProfileBlock, other []cover.ProfileBlock) []cover.ProfileBlock {
+	all := make([]mergeSide, 0, len(blocks)+len(other))
+	for _, b := range blocks {
+		all = append(all, mergeSide{block: b})
+	}
+	for _, b := range other {
+		all = append(all, mergeSide{block: b, isOther: true})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return isBlockBefore(all[i].block, all[j].block)
+	})
+
+	result := make([]cover.ProfileBlock, 0, len(all))
+	for len(all) > 0 {
+		// A cluster is a run of blocks that overlap each other, directly or through another block.
+		size := 1
+		endLine, endCol := all[0].block.EndLine, all[0].block.EndCol
+		for size < len(all) {
+			startLine, startCol := getMergeStart(all[size].block)
+			if !isBefore(startLine, startCol, endLine, endCol) {
+				break
+			}
+			if isBefore(endLine, endCol, all[size].block.EndLine, all[size].block.EndCol) {
+				endLine, endCol = all[size].block.EndLine, all[size].block.EndCol
+			}
+			size++
+		}
+		result = append(result, mergeCluster(mode, all[:size])...)
+		all = all[size:]
+	}
+	return result
+}
+
+func mergeCluster(mode string, cluster []mergeSide) []cover.ProfileBlock {
+	base := make([]cover.ProfileBlock, 0, len(cluster))
+	other := make([]cover.ProfileBlock, 0, len(cluster))
+	for _, side := range cluster {
+		if side.isOther {
+			other = append(other, side.block)
+		} else {
+			base = append(base, side.block)
+		}
+	}
+	if isFinerBlocks(other, base) {
+		base, other = other, base
+	}
+
+	result := make([]cover.ProfileBlock, 0, len(base))
+	for _, b := range base {
+		// The blocks of a line based profile all count the same executions of a statement block spanning their lines, so
+		// their counts aren't added up.
+		isOverlapped, overlapCount := false, 0
+		for _, o := range other {
+			if isBlockOverlapping(b, o) {
+				isOverlapped = true
+				if o.Count > overlapCount {
+					overlapCount = o.Count
+				}
+			}
+		}
+		if isOverlapped {
+			b.Count = mergeCounts(mode, b.Count, overlapCount)
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+// isFinerBlocks returns true if the blocks should be kept over the other side's blocks of a cluster. Statement blocks are
+// finer than line blocks, which don't know where statements start and end, then more blocks are finer than fewer, and
+// otherwise the earlier or larger blocks are kept.
+func isFinerBlocks(blocks []cover.ProfileBlock, other []cover.ProfileBlock) bool {
+	if len(blocks) == 0 || len(other) == 0 {
+		return len(blocks) > len(other)
+	} else if isLineBlocks(blocks) != isLineBlocks(other) {
+		return isLineBlocks(other)
+	} else if len(blocks) != len(other) {
+		return len(blocks) > len(other)
+	}
+	for i := range blocks {
+		if isBlockBefore(blocks[i], other[i]) || isBlockBefore(other[i], blocks[i]) {
+			return isBlockBefore(blocks[i], other[i])
+		} else if blocks[i].NumStmt != other[i].NumStmt {
+			return blocks[i].NumStmt > other[i].NumStmt
+		}
+	}
+	return false
+}
+
+// isLineBlocks returns true if the blocks were made from a line based input.
+func isLineBlocks(blocks []cover.ProfileBlock) bool {
+	for _, b := range blocks {
+		if b.EndCol == LineEndCol {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeCounts(mode string, count int, other int) int {
+	if mode == "set" {
+		if count > 0 || other > 0 {
+			return 1
+		}
+		return 0
+	}
+	return count + other
+}
+
+func isBlockOverlapping(b cover.ProfileBlock, o cover.ProfileBlock) bool {
+	bLine, bCol := getMergeStart(b)
+	oLine, oCol := getMergeStart(o)
+	return isBefore(bLine, bCol, o.EndLine, o.EndCol) && isBefore(oLine, oCol, b.EndLine, b.EndCol)
+}
+
+// getMergeStart returns the start of the block for finding overlaps. Line blocks start before any column of their line, so
+// they overlap a statement block that ends at the start of that line, as the line is part of the block in line based output.
+func getMergeStart(b cover.ProfileBlock) (int, int) {
+	if b.EndCol == LineEndCol {
+		return b.StartLine, 0
+	}
+	return b.StartLine, b.StartCol
+}
+
+// isBefore returns true if the first position comes strictly before the second.
+func isBefore(line int, col int, otherLine int, otherCol int) bool {
+	return line < otherLine || (line == otherLine && col < otherCol)
+}
@@ -0,0 +1,109 @@
+package lib
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestMergeProfileBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		blocks   []cover.ProfileBlock
+		other    []cover.ProfileBlock
+		expected []cover.ProfileBlock
+	}{
+		{
+			name:     "identical",
+			mode:     ModeCount,
+			blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 3}},
+			other:    []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 4}},
+			expected: []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 7}},
+		},
+		{
+			name:     "identical set",
+			mode:     ModeSet,
+			blocks:   []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 1}},
+			other:    []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 0}},
+			expected: []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 1}},
+		},
+		{
+			name:   "disjoint",
+			mode:   ModeCount,
+			blocks: []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 3}},
+			other:  []cover.ProfileBlock{{StartLine: 5, StartCol: 2, EndLine: 5, EndCol: 20, NumStmt: 1, Count: 0}},
+			expected: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 10, NumStmt: 2, Count: 3},
+				{StartLine: 5, StartCol: 2, EndLine: 5, EndCol: 20, NumStmt: 1, Count: 0},
+			},
+		},
+		{
+			name:   "overlapping",
+			mode:   ModeCount,
+			blocks: []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 5, EndCol: 2, NumStmt: 3, Count: 2}},
+			other: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 5, NumStmt: 2, Count: 1},
+				{StartLine: 3, StartCol: 5, EndLine: 5, EndCol: 2, NumStmt: 1, Count: 0},
+			},
+			expected: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 5, NumStmt: 2, Count: 3},
+				{StartLine: 3, StartCol: 5, EndLine: 5, EndCol: 2, NumStmt: 1, Count: 2},
+			},
+		},
+		{
+			name:   "overlapping same granularity",
+			mode:   ModeCount,
+			blocks: []cover.ProfileBlock{{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 2}},
+			other:  []cover.ProfileBlock{{StartLine: 2, StartCol: 1, EndLine: 4, EndCol: 2, NumStmt: 2, Count: 1}},
+			expected: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 3},
+			},
+		},
+		{
+			name: "mixed granularity",
+			mode: ModeCount,
+			blocks: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 10, EndLine: 6, EndCol: 3, NumStmt: 2, Count: 1},
+			},
+			other: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 1, EndLine: 5, EndCol: LineEndCol, NumStmt: 1, Count: 2},
+				{StartLine: 6, StartCol: 1, EndLine: 6, EndCol: LineEndCol, NumStmt: 1, Count: 2},
+				{StartLine: 8, StartCol: 1, EndLine: 8, EndCol: LineEndCol, NumStmt: 1, Count: 0},
+			},
+			expected: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 10, EndLine: 6, EndCol: 3, NumStmt: 2, Count: 3},
+				{StartLine: 8, StartCol: 1, EndLine: 8, EndCol: LineEndCol, NumStmt: 1, Count: 0},
+			},
+		},
+		{
+			name: "mixed granularity block ending at a line start",
+			mode: ModeCount,
+			blocks: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 2, EndLine: 6, EndCol: 1, NumStmt: 1, Count: 3},
+			},
+			other: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 1, EndLine: 5, EndCol: LineEndCol, NumStmt: 1, Count: 3},
+				{StartLine: 6, StartCol: 1, EndLine: 6, EndCol: LineEndCol, NumStmt: 1, Count: 3},
+			},
+			expected: []cover.ProfileBlock{
+				{StartLine: 5, StartCol: 2, EndLine: 6, EndCol: 1, NumStmt: 1, Count: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := MergeProfileBlocks(tt.mode, tt.blocks, tt.other)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("MergeProfileBlocks() = %v, want %v", actual, tt.expected)
+			}
+			// The order of the inputs mustn't change the result.
+			actual = MergeProfileBlocks(tt.mode, tt.other, tt.blocks)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("MergeProfileBlocks() reversed = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
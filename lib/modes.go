@@ -0,0 +1,115 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// The coverage modes of `go test -covermode`
+const (
+	ModeSet    = "set"
+	ModeCount  = "count"
+	ModeAtomic = "atomic"
+	// The mode of line based inputs, LCOV and Cobertura, which have hit counts but take the mode of the other inputs
+	ModeAny = ""
+)
+
+// InputProfiles are the profiles read from a single input
+type InputProfiles struct {
+	// The input the profiles were read from
+	Input string
+	// The distinct coverage modes of the profiles, without ModeAny
+	Modes []string
+	// The profiles read from the input
+	Profiles []*cover.Profile
+}
+
+// NewInputProfiles wraps the profiles read from an input with their coverage modes.
+func NewInputProfiles(input string, profiles []*cover.Profile) InputProfiles {
+	modes := make([]string, 0)
+	for _, profile := range profiles {
+		if profile.Mode != ModeAny && !containsString(modes, profile.Mode) {
+			modes = append(modes, profile.Mode)
+		}
+	}
+	sort.Strings(modes)
+	return InputProfiles{Input: input, Modes: modes, Profiles: profiles}
+}
+
+// HasHitCounts returns true if the counts of the mode are numbers of executions, rather than just whether it was executed.
+func HasHitCounts(mode string) bool {
+	return mode == ModeCount || mode == ModeAtomic
+}
+
+// ResolveMode returns the coverage mode of the report for the inputs. Set mode can't be mixed with the count modes, unless
+// coerce is set, which makes set the mode regardless. Count and atomic inputs can be mixed and are reported as count.
+// Inputs without profiles, and line based inputs, have no mode and are ignored unless they're all there is.
+func ResolveMode(inputs []InputProfiles, coerce string) (string, error) {
+	if coerce == ModeSet {
+		return ModeSet, nil
+	}
+
+	modes := make([]string, 0)
+	modeInputs := make([]InputProfiles, 0, len(inputs))
+	hasProfiles := false
+	for _, input := range inputs {
+		hasProfiles = hasProfiles || len(input.Profiles) > 0
+		if len(input.Modes) > 0 {
+			modeInputs = append(modeInputs, input)
+		}
+		for _, mode := range input.Modes {
+			if !containsString(modes, mode) {
+				modes = append(modes, mode)
+			}
+		}
+	}
+
+	if containsString(modes, ModeSet) && len(modes) > 1 {
+		return "", MixedModesError(modeInputs)
+	} else if len(modes) == 1 {
+		return modes[0], nil
+	} else if len(modes) == 0 && hasProfiles {
+		// Only line based inputs, which keep their hit counts
+		return ModeCount, nil
+	} else if len(modes) == 0 {
+		return ModeSet, nil
+	}
+	return ModeCount, nil
+}
+
+// CoerceProfiles sets the mode of the profiles, capping the counts at 1 when the mode is set.
+func CoerceProfiles(profiles []*cover.Profile, mode string) {
+	for _, profile := range profiles {
+		if mode == ModeSet && profile.Mode != ModeSet {
+			for i := range profile.Blocks {
+				if profile.Blocks[i].Count > 0 {
+					profile.Blocks[i].Count = 1
+				}
+			}
+		}
+		profile.Mode = mode
+	}
+}
+
+func describeInputModes(inputs []InputProfiles) string {
+	descriptions := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		if len(input.Modes) == 0 {
+			continue
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s is %s", input.Input, strings.Join(input.Modes, " and ")))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+)
+
+func TestResolveMode(t *testing.T) {
+	set := newModeInput("set.out", ModeSet)
+	count := newModeInput("count.out", ModeCount)
+	atomic := newModeInput("atomic.out", ModeAtomic)
+	lcov := newModeInput("lcov.info", ModeAny)
+	empty := newModeInput("empty.out")
+
+	tests := []struct {
+		name     string
+		inputs   []InputProfiles
+		coerce   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "set", inputs: []InputProfiles{set, set}, expected: ModeSet},
+		{name: "count", inputs: []InputProfiles{count}, expected: ModeCount},
+		{name: "atomic", inputs: []InputProfiles{atomic}, expected: ModeAtomic},
+		{name: "count and atomic", inputs: []InputProfiles{count, atomic}, expected: ModeCount},
+		{name: "set and count", inputs: []InputProfiles{set, count}, wantErr: true},
+		{name: "set and count coerced", inputs: []InputProfiles{set, count}, coerce: ModeSet, expected: ModeSet},
+		{name: "line based with set", inputs: []InputProfiles{lcov, set}, expected: ModeSet},
+		{name: "line based with count", inputs: []InputProfiles{count, lcov}, expected: ModeCount},
+		{name: "line based only", inputs: []InputProfiles{lcov}, expected: ModeCount},
+		{name: "empty with set", inputs: []InputProfiles{empty, set}, expected: ModeSet},
+		{name: "no profiles", inputs: []InputProfiles{empty}, expected: ModeSet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ResolveMode(tt.inputs, tt.coerce)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if actual != tt.expected {
+				t.Errorf("ResolveMode() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMixedModesError(t *testing.T) {
+	inputs := []InputProfiles{
+		newModeInput("set.out", ModeSet),
+		newModeInput("empty.out"),
+		newModeInput("both.out", ModeCount, ModeSet),
+	}
+	_, err := ResolveMode(inputs, "")
+
+	expected := "Error(406): Coverage modes set and count can't be mixed: set.out is set, both.out is count and set. " +
+		"Use --mode-coerce=set to report them all as set"
+	if err == nil || err.Error() != expected {
+		t.Errorf("ResolveMode() error = %v, want %s", err, expected)
+	}
+}
+
+func TestCoerceProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  cover.Profile
+		mode     string
+		expected cover.Profile
+	}{
+		{
+			name:     "count to set",
+			profile:  cover.Profile{Mode: ModeCount, Blocks: []cover.ProfileBlock{{NumStmt: 1, Count: 5}, {NumStmt: 1}}},
+			mode:     ModeSet,
+			expected: cover.Profile{Mode: ModeSet, Blocks: []cover.ProfileBlock{{NumStmt: 1, Count: 1}, {NumStmt: 1}}},
+		},
+		{
+			name:     "line based to set",
+			profile:  cover.Profile{Mode: ModeAny, Blocks: []cover.ProfileBlock{{NumStmt: 1, Count: 3}}},
+			mode:     ModeSet,
+			expected: cover.Profile{Mode: ModeSet, Blocks: []cover.ProfileBlock{{NumStmt: 1, Count: 1}}},
+		},
+		{
+			name:     "atomic to count",
+			profile:  cover.Profile{Mode: ModeAtomic, Blocks: []cover.ProfileBlock{{NumStmt: 2, Count: 7}}},
+			mode:     ModeCount,
+			expected: cover.Profile{Mode: ModeCount, Blocks: []cover.ProfileBlock{{NumStmt: 2, Count: 7}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := tt.profile
+			CoerceProfiles([]*cover.Profile{&profile}, tt.mode)
+			if !reflect.DeepEqual(profile, tt.expected) {
+				t.Errorf("CoerceProfiles() = %v, want %v", profile, tt.expected)
+			}
+		})
+	}
+}
+
+// newModeInput makes the input with a profile for each mode.
+func newModeInput(input string, modes ...string) InputProfiles {
+	profiles := make([]*cover.Profile, 0, len(modes))
+	for _, mode := range modes {
+		profiles = append(profiles, &cover.Profile{FileName: input, Mode: mode})
+	}
+	return NewInputProfiles(input, profiles)
+}
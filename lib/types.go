@@ -21,10 +21,30 @@ type AppConfig struct {
 	Output string `json:"output" yaml:"output" xml:"output"`
 	// The input coverage file from `go test -coverprofile`
 	Input []string `json:"input" yaml:"input" xml:"input"`
+	// The patterns of inputs to skip when expanding globs and directories
+	InputExclude []string `json:"inputExclude" yaml:"inputExclude" xml:"inputExclude"`
+	// The mode to coerce the inputs to when their modes differ, empty to fail instead
+	ModeCoerce string `json:"modeCoerce" yaml:"modeCoerce" xml:"modeCoerce"`
 	// The source code folder location on disk
 	SourceDir string `json:"source" yaml:"source" xml:"source"`
 	// The display name of the package
 	ProjectName string `json:"ProjectName" yaml:"ProjectName" xml:"ProjectName"`
+	// The number of least covered files to list in summaries, zero for none
+	LeastCovered int `json:"leastCovered" yaml:"leastCovered" xml:"leastCovered"`
+	// The maximum folder depth to show in tree outputs, zero for unlimited
+	Depth int `json:"depth" yaml:"depth" xml:"depth"`
+	// The kind of rows to write to CSV outputs, files, folders, or all
+	CsvKind string `json:"csvKind" yaml:"csvKind" xml:"csvKind"`
+	// The maximum number of annotations to write, zero for unlimited
+	MaxAnnotations int `json:"maxAnnotations" yaml:"maxAnnotations" xml:"maxAnnotations"`
+	// The coverage percentage below which functions are reported as findings, zero for none
+	Threshold float64 `json:"threshold" yaml:"threshold" xml:"threshold"`
+	// The CI service name for job payloads
+	ServiceName string `json:"serviceName" yaml:"serviceName" xml:"serviceName"`
+	// The CI service job id for job payloads
+	ServiceJobId string `json:"serviceJobId" yaml:"serviceJobId" xml:"serviceJobId"`
+	// The lines of context to show around uncovered lines in source listings, negative for the whole file
+	Context int `json:"context" yaml:"context" xml:"context"`
 }
 
 // The basic meta data for the report
@@ -35,6 +55,13 @@ type ReportMeta struct {
 	CommonRoot string `json:"commonRoot" yaml:"commonRoot" xml:"commonRoot"`
 	// The parent of the CommonRoot directory path
 	ParentRoot string `json:"parentRoot" yaml:"parentRoot" xml:"parentRoot"`
+	// The coverage mode of all reported files, one of set, count, or atomic
+	Mode string `json:"mode" yaml:"mode" xml:"mode"`
+}
+
+// HasHitCounts returns true if the block counts of the report are numbers of executions.
+func (rm ReportMeta) HasHitCounts() bool {
+	return HasHitCounts(rm.Mode)
 }
 
 type ReportContainer interface {
@@ -73,6 +100,8 @@ func NewReportContext(config AppConfig, meta ReportMeta, isFullRpt bool) ReportC
 	absOutPath, err := filepath.Abs(config.Output)
 	if err != nil {
 		HandleStopError(UnresolvablePathError(config.Output))
+	} else if config.Output == StdPath {
+		absOutPath = StdPath
 	}
 
 	return ReportContext{
@@ -132,15 +161,22 @@ func (rc *ReportContext) AddFolder(folder *ReportedFolder) {
 	}
 }
 
-// AddFile adds a ReportedFile to the context.ReportedFiles, creating the folder if it doesn't already exist.
+// AddFile adds a ReportedFile to the context.ReportedFiles, merging it into the existing file for the same source file.
 func (rc *ReportContext) AddFile(file *ReportedFile) {
-	if _, exists := rc.ContainsFile(file.SourceFile); !exists {
+	if existing, exists := rc.ContainsFile(file.SourceFile); !exists {
 		rc.ReportedFiles = append(rc.ReportedFiles, file)
+	} else if existing != file {
+		existing.Merge(file)
 	}
 }
 
 // AddFolderFile adds a ReportedFile to the context.ReportedFolders, creating the folder if it doesn't already exist.
 func (rc *ReportContext) AddFolderFile(folderPath string, file *ReportedFile) {
+	if existing, exists := rc.ContainsFile(file.SourceFile); exists {
+		// The folder holds the same file as the context, so merging it once covers both.
+		existing.Merge(file)
+		return
+	}
 	var node ReportContainer = rc
 	relDirs := strings.Split(path.Dir(file.SourceFile)[len(rc.Config.SourceDir):], string(os.PathSeparator))[1:]
 	for i := range relDirs {
@@ -161,6 +197,17 @@ func (rc *ReportContext) AddFolderFile(folderPath string, file *ReportedFile) {
 	rc.AddFile(file)
 }
 
+// GetRootFiles returns the reported files that sit directly in the source directory, and so belong to no folder.
+func (rc *ReportContext) GetRootFiles() []*ReportedFile {
+	files := make([]*ReportedFile, 0)
+	for _, file := range rc.ReportedFiles {
+		if path.Dir(file.GetRelPath()) == "." {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
 func (rc *ReportContext) GetAllFolders() []*ReportedFolder {
 	folders := make([]*ReportedFolder, 0)
 	for _, folder := range rc.ReportedFolders {
@@ -170,14 +217,22 @@ func (rc *ReportContext) GetAllFolders() []*ReportedFolder {
 	return folders
 }
 
-// UpdateCoverage updates the coverage percentage for each folder in the context.ReportedFolders based on the covered files.
+// UpdateCoverage updates the coverage percentage for each folder in the context.ReportedFolders, and for the whole report
+// from all the reported files, including those directly in the source directory.
 func (rc *ReportContext) UpdateCoverage() {
-	blocks := make([]cover.ProfileBlock, 0)
 	for _, folder := range rc.ReportedFolders {
 		folder.UpdateCoverage()
-		blocks = append(blocks, folder.GetProfileBlocks()...)
 	}
-	rc.CoveredPct = GetCoveredPct(blocks, true)
+	rc.CoveredPct = GetCoveredPct(rc.GetProfileBlocks(), true)
+}
+
+// GetProfileBlocks gets the covered blocks for all the reported files.
+func (rc *ReportContext) GetProfileBlocks() []cover.ProfileBlock {
+	blocks := make([]cover.ProfileBlock, 0)
+	for _, file := range rc.ReportedFiles {
+		blocks = append(blocks, file.Profile.Blocks...)
+	}
+	return blocks
 }
 
 // A ReportedFolder is a meta-level representation of a folder of ReportedFile entries
@@ -236,10 +291,12 @@ func (rf *ReportedFolder) ContainsFile(filePath string) (*ReportedFile, bool) {
 	return nil, false
 }
 
-// AddFile adds a ReportedFile to the folder, if it doesn't already exist.
+// AddFile adds a ReportedFile to the folder, merging it into the existing file for the same source file.
 func (rf *ReportedFolder) AddFile(file *ReportedFile) {
-	if _, exists := rf.ContainsFile(file.SourceFile); !exists {
+	if existing, exists := rf.ContainsFile(file.SourceFile); !exists {
 		rf.ReportedFiles = append(rf.ReportedFiles, file)
+	} else if existing != file {
+		existing.Merge(file)
 	}
 }
 
@@ -269,6 +326,11 @@ func (rc *ReportedFolder) GetAllFolders() []*ReportedFolder {
 	return folders
 }
 
+// GetRelPath gets the slash separated path of the folder relative to the common root.
+func (rf *ReportedFolder) GetRelPath() string {
+	return GetRelPath(rf.Meta.CommonRoot, rf.FolderPath)
+}
+
 // WithExtension gets the output file path for the folder with the specified extension.
 func (rf *ReportedFolder) WithExtension(ext string) string {
 	return SwapFileExt(rf.OutFilePath, ext)
@@ -307,6 +369,16 @@ type ReportedBlock struct {
 	Covered bool `json:"covered"`
 }
 
+// LineHits is the number of times a single source line was executed
+type LineHits struct {
+	// The line number
+	Line int `json:"line" yaml:"line" xml:"line"`
+	// The number of hits for the line
+	Hits int `json:"hits" yaml:"hits" xml:"hits"`
+	// Whether the line was hit but has a block that wasn't
+	IsPartial bool `json:"isPartial" yaml:"isPartial" xml:"isPartial"`
+}
+
 // PathTuple is a tuple of a displayable name and a navigable path
 type PathTuple struct {
 	// The displayable name for this path
@@ -374,11 +446,23 @@ func (rf *ReportedFile) WithExtension(ext string) string {
 	return SwapFileExt(rf.OutFilePath, ext)
 }
 
+// GetRelPath gets the slash separated path of the file relative to the common root.
+func (rf *ReportedFile) GetRelPath() string {
+	return GetRelPath(rf.Meta.CommonRoot, rf.SourceFile)
+}
+
 // GetCoveredPct returns the percentage of statements covered for all blocks in this file.
 func (rf *ReportedFile) GetCoveredPct(multiplied bool) (result float64) {
 	return GetCoveredPct(rf.Profile.Blocks, multiplied)
 }
 
+// Merge merges the coverage blocks of other, a profile of the same source file, into this file.
+func (rf *ReportedFile) Merge(other *ReportedFile) {
+	rf.Profile.Blocks = MergeProfileBlocks(rf.Profile.Mode, rf.Profile.Blocks, other.Profile.Blocks)
+	rf.ReportedLines, rf.CoveredLines = GetProfiledLines(rf.Profile)
+	rf.CoveredPct = GetCoveredPct(rf.Profile.Blocks, true)
+}
+
 // GetSourceCode returns the source code for this file.
 func (rf *ReportedFile) GetSourceCode() (result string, err error) {
 	if !rf.isSourceRead {
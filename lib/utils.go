@@ -6,17 +6,26 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/cover"
 )
 
+// StdPath is the path used in place of a file name to mean stdin or stdout.
+const StdPath = "-"
+
 func MakeFileDir(filePath string) error {
 	dir := path.Dir(filePath)
 	return os.MkdirAll(dir, os.ModePerm)
 }
 
+// MakeFile creates the file and its directory, or returns stdout when the path is "-".
 func MakeFile(filePath string) (*os.File, error) {
+	if filePath == StdPath {
+		return os.Stdout, nil
+	}
+
 	err := MakeFileDir(filePath)
 	if err != nil {
 		return nil, err
@@ -142,27 +151,16 @@ func Offset(reader *bytes.Reader) int {
 	return int(reader.Size()) - reader.Len()
 }
 
-// Calculates the covered percentage for a profile, optionally multiplied by 100.
+// Calculates the percentage of statements covered by the blocks, whatever the mode, optionally multiplied by 100.
 func GetCoveredPct(blocks []cover.ProfileBlock, multiplied bool) (result float64) {
-	max := 0.0
-	covCount := 0
-	stmtCount := 0
-	for _, b := range blocks {
-		max = math.Max(max, float64(b.Count))
-		covCount += b.Count
-		stmtCount += b.NumStmt
-	}
-	if max > 1 {
-		result = float64(covCount) / float64(stmtCount)
-	} else {
-		result = float64(covCount) / float64(len(blocks))
+	statements, coveredStatements := GetStatementCounts(blocks)
+	if statements == 0 {
+		return 0
 	}
+	result = float64(coveredStatements) / float64(statements)
 	if multiplied {
 		result *= 100
 	}
-	if math.IsNaN(result) {
-		result = 0
-	}
 	return
 }
 
@@ -211,3 +209,122 @@ func newReportedBlock(b *cover.ProfileBlock, covered bool) ReportedBlock {
 		Covered:   covered,
 	}
 }
+
+// GetRelPath returns the slash separated path of filePath relative to root, or filePath itself if it can't be made relative.
+func GetRelPath(root, filePath string) string {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+// GetStatementCounts returns the total and covered statement counts for the blocks.
+func GetStatementCounts(blocks []cover.ProfileBlock) (total, covered int) {
+	for _, b := range blocks {
+		total += b.NumStmt
+		if b.Count > 0 {
+			covered += b.NumStmt
+		}
+	}
+	return
+}
+
+// GetLineHits expands the blocks into per-line hit counts, ordered by line number.
+// When more than one block touches a line, the highest count wins, and the line is partial if any of them wasn't hit.
+func GetLineHits(blocks []cover.ProfileBlock) []LineHits {
+	hitMap := make(map[int]int)
+	missedLines := make(map[int]bool)
+	for _, b := range blocks {
+		for line := b.StartLine; line <= b.EndLine; line++ {
+			if hits, exists := hitMap[line]; !exists || b.Count > hits {
+				hitMap[line] = b.Count
+			}
+			if b.Count == 0 {
+				missedLines[line] = true
+			}
+		}
+	}
+
+	result := make([]LineHits, 0, len(hitMap))
+	for line, hits := range hitMap {
+		result = append(result, LineHits{Line: line, Hits: hits, IsPartial: hits > 0 && missedLines[line]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
+
+// GetCoveredLineCount returns the number of lines with at least one hit.
+func GetCoveredLineCount(lines []LineHits) (covered int) {
+	for _, l := range lines {
+		if l.Hits > 0 {
+			covered++
+		}
+	}
+	return
+}
+
+// GetBlockCounts returns the total and covered block counts for the blocks.
+func GetBlockCounts(blocks []cover.ProfileBlock) (total, covered int) {
+	for _, b := range blocks {
+		total++
+		if b.Count > 0 {
+			covered++
+		}
+	}
+	return
+}
+
+// IsColorTerminal returns true if the file is a terminal and colors haven't been disabled with NO_COLOR.
+func IsColorTerminal(file *os.File) bool {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// GetRepoRoot returns the closest directory at or above dir that contains a .git entry, or dir if there is none.
+func GetRepoRoot(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for current := absDir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		if current == filepath.Dir(current) {
+			return absDir
+		}
+	}
+}
+
+// SortProfileBlocks sorts the blocks by their start, then end positions.
+func SortProfileBlocks(blocks []cover.ProfileBlock) {
+	sort.Slice(blocks, func(i, j int) bool {
+		return isBlockBefore(blocks[i], blocks[j])
+	})
+}
+
+func isBlockBefore(bi cover.ProfileBlock, bj cover.ProfileBlock) bool {
+	if bi.StartLine != bj.StartLine {
+		return bi.StartLine < bj.StartLine
+	}
+	if bi.StartCol != bj.StartCol {
+		return bi.StartCol < bj.StartCol
+	}
+	if bi.EndLine != bj.EndLine {
+		return bi.EndLine < bj.EndLine
+	}
+	return bi.EndCol < bj.EndCol
+}